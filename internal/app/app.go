@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/r-mol/ObsidianBot/internal/clock"
 	"github.com/r-mol/ObsidianBot/internal/configs"
+	"github.com/r-mol/ObsidianBot/internal/models"
 	"github.com/r-mol/ObsidianBot/internal/repository"
 	"github.com/r-mol/ObsidianBot/internal/routes"
 	"github.com/r-mol/ObsidianBot/internal/usecases"
@@ -20,6 +22,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/r-mol/ObsidianBot/pkg/tgbot"
+	"github.com/r-mol/ObsidianBot/pkg/transcriber"
+	"github.com/r-mol/ObsidianBot/pkg/webpage"
 )
 
 func Run(ctx context.Context, configPath string) error {
@@ -34,50 +38,112 @@ func Run(ctx context.Context, configPath string) error {
 	}
 
 	// init repo
-	repo := repository.New(config.Server.ObsidianAbsolutePath)
+	repo := repository.New(config.Server.ObsidianAbsolutePath, config.Server.LockDir)
+
+	// users sharing a vault share its repository
+	reposByPath := map[string]usecases.Repository{config.Server.ObsidianAbsolutePath: repo}
+	users := make([]*models.User, 0, len(config.Server.Users))
+	userRepos := make(map[int64]usecases.Repository)
+	for _, user := range config.Server.Users {
+		users = append(users, &models.User{
+			ID:   user.ID,
+			Name: user.Name,
+			Role: user.Role,
+		})
+
+		if _, ok := reposByPath[user.VaultPath]; !ok {
+			reposByPath[user.VaultPath] = repository.New(user.VaultPath, config.Server.LockDir)
+		}
+		userRepos[user.ID] = reposByPath[user.VaultPath]
+	}
 
 	// init usecases
-	obsidianUsecase := usecases.NewObsidian(repo, config.Server.UserID)
+	clk := clock.New(config.Server.Location)
+	obsidianUsecase := usecases.NewObsidian(repo, userRepos, config.Vault, clk, transcriber.New(config.Transcriber), webpage.NewFetcher(nil))
+
+	err = obsidianUsecase.CheckVaults()
+	if err != nil {
+		return fmt.Errorf("check vaults layout: %w", err)
+	}
 
 	// init routes
-	botRoute := routes.NewBot(obsidianUsecase, config.Server.UserID)
+	botRoute := routes.NewBot(obsidianUsecase, users)
 
 	// init tg menu
 	tgMenu := map[string]routes.Command{
 		"shopping_list": {
-			DescRu:  "Показать весь список покупок",
-			DescEn:  "Get shopping list",
-			Handler: obsidianUsecase.GetShoppingList,
+			DescRu:   "Показать весь список покупок",
+			DescEn:   "Get shopping list",
+			Role:     models.RoleReadOnly,
+			Handler:  obsidianUsecase.GetShoppingList,
+			Keyboard: botRoute.ShoppingListMarkup,
 		},
 		"clear_shopping_list": {
 			DescRu:  "Очисть список покупок",
 			DescEn:  "Clear shopping list",
+			Role:    models.RoleEditor,
 			Handler: obsidianUsecase.ClearShoppingList,
 		},
 		"remove_item": {
 			DescRu:  "Удалить из списка покупок",
 			DescEn:  "Remove item from shopping list",
+			Role:    models.RoleEditor,
 			Handler: obsidianUsecase.RemoveItemsFromShoppingList,
 		},
 		"wish_list": {
-			DescRu:  "Показать весь список желаний",
-			DescEn:  "Get wish list",
+			DescRu:  "Показать список желаний, сортировка price или priority",
+			DescEn:  "Get wish list, sort by price or priority",
+			Role:    models.RoleReadOnly,
 			Handler: obsidianUsecase.GetWishList,
 		},
+		"wish_add": {
+			DescRu:  "Добавить в список желаний",
+			DescEn:  "Add item to wish list",
+			Role:    models.RoleEditor,
+			Handler: obsidianUsecase.AddItemsToWishList,
+		},
+		"wish_remove": {
+			DescRu:  "Удалить из списка желаний",
+			DescEn:  "Remove item from wish list",
+			Role:    models.RoleEditor,
+			Handler: obsidianUsecase.RemoveItemsFromWishList,
+		},
 		"reading_list": {
-			DescRu:  "Показать список книг",
-			DescEn:  "Get reading list",
+			DescRu:  "Показать список книг, фильтры status:, year:, rating:",
+			DescEn:  "Get reading list, filters status:, year:, rating:",
+			Role:    models.RoleReadOnly,
 			Handler: obsidianUsecase.GetReadingList,
 		},
+		"reading_stats": {
+			DescRu:  "Статистика чтения за год",
+			DescEn:  "Get reading stats for this year",
+			Role:    models.RoleReadOnly,
+			Handler: obsidianUsecase.GetReadingStats,
+		},
 		"watching_list": {
-			DescRu:  "Показать список фильмов",
-			DescEn:  "Get watching list",
+			DescRu:  "Показать список фильмов, фильтры status:, year:, rating:",
+			DescEn:  "Get watching list, filters status:, year:, rating:",
+			Role:    models.RoleReadOnly,
 			Handler: obsidianUsecase.GetWatchingList,
 		},
+		"undo": {
+			DescRu:  "Отменить последнее изменение",
+			DescEn:  "Undo the last change",
+			Role:    models.RoleEditor,
+			Handler: obsidianUsecase.Undo,
+		},
+		"progress": {
+			DescRu:  "Изменить прогресс книги или фильма",
+			DescEn:  "Set progress of a book or film",
+			Role:    models.RoleEditor,
+			Handler: obsidianUsecase.SetProgress,
+		},
 		"inbox": {
-			DescRu:  "Показать inbox",
-			DescEn:  "Get inbox",
-			Handler: obsidianUsecase.GetInboxItems,
+			DescRu:   "Показать и разобрать inbox",
+			DescEn:   "Get and sort inbox",
+			Role:     models.RoleReadOnly,
+			Handler:  obsidianUsecase.GetInboxItems,
+			Keyboard: botRoute.InboxMarkup,
 		},
 	}
 
@@ -87,34 +153,44 @@ func Run(ctx context.Context, configPath string) error {
 	}
 
 	botRoute.TextMessageHandler(ctx, b)
+	botRoute.UndoHandler(ctx, b)
+	botRoute.ShoppingListHandler(ctx, b)
+	botRoute.InboxHandler(ctx, b)
+	botRoute.MediaHandler(ctx, b)
+	botRoute.LocationHandler(ctx, b)
 
-	c := cron.New()
-	cronExpr := "0 6 * * *" // Every Sunday and Wednesday at 6:00 AM
+	c := cron.New(cron.WithLocation(clk.Location()))
 
-	// Schedule NotifyUser function
-	_, err = c.AddFunc(cronExpr, func() {
-		err := botRoute.NotifyUser(ctx, b)
+	for _, schedule := range config.Schedules {
+		schedule := schedule
+		handler := scheduleHandler(schedule, obsidianUsecase)
+
+		_, err = c.AddFunc(schedule.Spec(), func() {
+			err := botRoute.NotifyUsers(ctx, b, schedule.UserIDs, handler)
+			if err != nil {
+				log.Printf("error running schedule %q: %v", schedule.Name, err)
+			} else {
+				log.Printf("Schedule %q notification sent successfully", schedule.Name)
+			}
+		})
 		if err != nil {
-			log.Printf("error notifying user: %v", err)
-		} else {
-			log.Println("Notification sent successfully")
+			return fmt.Errorf("add schedule %q to cron job: %w", schedule.Name, err)
 		}
-	})
-
-	if err != nil {
-		return fmt.Errorf("add func to cron job: %w", err)
 	}
 
 	eg, ctx := errgroup.WithContext(ctx)
 
-	eg.Go(func() error {
-		if err := http.ListenAndServe(":"+config.Server.Port, nil); err != http.ErrServerClosed {
-			return err
-		} else {
-			log.Info("HTTP server stopped")
-			return nil
-		}
-	})
+	// telegram sends updates to the HTTP server in webhook mode only
+	if config.TgBot.Mode == tgbot.ModeWebhook {
+		eg.Go(func() error {
+			if err := http.ListenAndServe(":"+config.Server.Port, nil); err != http.ErrServerClosed {
+				return err
+			} else {
+				log.Info("HTTP server stopped")
+				return nil
+			}
+		})
+	}
 
 	eg.Go(func() error {
 		b.Start()
@@ -146,6 +222,29 @@ func Run(ctx context.Context, configPath string) error {
 	return nil
 }
 
+type notifyHandler = func(ctx context.Context, msg string) (string, error)
+
+type scheduleUsecase interface {
+	RememberAboutInbox(ctx context.Context, msg string) (string, error)
+	GetShoppingList(ctx context.Context, msg string) (string, error)
+	GetReadingList(ctx context.Context, msg string) (string, error)
+}
+
+func scheduleHandler(schedule *configs.ScheduleConfig, obsidianUsecase scheduleUsecase) notifyHandler {
+	switch schedule.Action {
+	case configs.ScheduleActionShoppingList:
+		return obsidianUsecase.GetShoppingList
+	case configs.ScheduleActionReadingList:
+		return obsidianUsecase.GetReadingList
+	case configs.ScheduleActionMessage:
+		return func(context.Context, string) (string, error) {
+			return schedule.Message, nil
+		}
+	default:
+		return obsidianUsecase.RememberAboutInbox
+	}
+}
+
 func GetApp() *cobra.Command {
 	var configPath string
 
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/r-mol/ObsidianBot/internal/configs"
+)
+
+type stubScheduleUsecase struct{}
+
+func (stubScheduleUsecase) RememberAboutInbox(context.Context, string) (string, error) {
+	return "inbox", nil
+}
+
+func (stubScheduleUsecase) GetShoppingList(context.Context, string) (string, error) {
+	return "shopping", nil
+}
+
+func (stubScheduleUsecase) GetReadingList(context.Context, string) (string, error) {
+	return "reading", nil
+}
+
+func TestScheduleHandler(t *testing.T) {
+	tests := []struct {
+		action configs.ScheduleAction
+		want   string
+	}{
+		{action: "", want: "inbox"},
+		{action: configs.ScheduleActionInboxReminder, want: "inbox"},
+		{action: configs.ScheduleActionShoppingList, want: "shopping"},
+		{action: configs.ScheduleActionReadingList, want: "reading"},
+		{action: configs.ScheduleActionMessage, want: "Water the plants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			schedule := &configs.ScheduleConfig{Action: tt.action, Message: "Water the plants"}
+
+			got, err := scheduleHandler(schedule, stubScheduleUsecase{})(context.Background(), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("handler of %q = %q, want %q", tt.action, got, tt.want)
+			}
+		})
+	}
+}
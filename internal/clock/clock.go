@@ -0,0 +1,39 @@
+// Package clock provides the current time in the configured timezone.
+package clock
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+	Location() *time.Location
+}
+
+type system struct {
+	location *time.Location
+}
+
+// New returns a clock reading the system time in the given location.
+func New(location *time.Location) Clock {
+	return &system{location: location}
+}
+
+func (c *system) Now() time.Time {
+	return time.Now().In(c.location)
+}
+
+func (c *system) Location() *time.Location {
+	return c.location
+}
+
+// Frozen is a clock which always returns the same moment, for tests.
+type Frozen struct {
+	Time time.Time
+}
+
+func (c Frozen) Now() time.Time {
+	return c.Time
+}
+
+func (c Frozen) Location() *time.Location {
+	return c.Time.Location()
+}
@@ -3,15 +3,21 @@ package configs
 import (
 	"fmt"
 	"os"
+	_ "time/tzdata"
 
+	"github.com/r-mol/ObsidianBot/internal/models"
 	"github.com/r-mol/ObsidianBot/pkg/tgbot"
+	"github.com/r-mol/ObsidianBot/pkg/transcriber"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server *ServerConfig `yaml:"server"`
-	TgBot  *tgbot.Config `yaml:"tg_bot"`
+	Server      *ServerConfig       `yaml:"server"`
+	TgBot       *tgbot.Config       `yaml:"tg_bot"`
+	Schedules   []*ScheduleConfig   `yaml:"schedules"`
+	Vault       *models.VaultConfig `yaml:"vault"`
+	Transcriber *transcriber.Config `yaml:"transcriber"`
 }
 
 func validateConfig(config *Config) error {
@@ -26,10 +32,34 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("validate server config: %w", err)
 	}
 
+	if err := validateSchedulesConfig(config); err != nil {
+		return fmt.Errorf("validate schedules config: %w", err)
+	}
+
+	if config.Vault == nil {
+		config.Vault = models.DefaultVaultConfig()
+	}
+
+	if err := validateVaultConfig(config.Vault); err != nil {
+		return fmt.Errorf("validate vault config: %w", err)
+	}
+
 	if err := tgbot.ValidateConfig(config.TgBot); err != nil {
 		return fmt.Errorf("validate telegram config: %w", err)
 	}
 
+	if config.TgBot.Mode == tgbot.ModeWebhook && config.Server.Port == "" {
+		return xerrors.New("\"server.port\" is required in webhook mode")
+	}
+
+	if config.Transcriber == nil {
+		config.Transcriber = &transcriber.Config{}
+	}
+
+	if err := transcriber.ValidateConfig(config.Transcriber); err != nil {
+		return fmt.Errorf("validate transcriber config: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,31 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/r-mol/ObsidianBot/pkg/tgbot"
+)
+
+func TestValidateConfigPort(t *testing.T) {
+	tests := []struct {
+		mode    tgbot.Mode
+		port    string
+		wantErr bool
+	}{
+		{mode: tgbot.ModePolling},
+		{mode: tgbot.ModeWebhook, port: "8081"},
+		{mode: tgbot.ModeWebhook, wantErr: true},
+		{mode: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		config := &Config{
+			Server: &ServerConfig{Port: tt.port, UserID: 1, ObsidianAbsolutePath: t.TempDir()},
+			TgBot:  &tgbot.Config{Token: "token", Mode: tt.mode, WebhookUrl: "https://example.com/bot"},
+		}
+
+		if err := validateConfig(config); (err != nil) != tt.wantErr {
+			t.Errorf("validateConfig() [mode = %q, port = %q] error = %v, wantErr %v", tt.mode, tt.port, err, tt.wantErr)
+		}
+	}
+}
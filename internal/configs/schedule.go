@@ -0,0 +1,99 @@
+package configs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"golang.org/x/xerrors"
+)
+
+type ScheduleAction string
+
+const (
+	ScheduleActionInboxReminder ScheduleAction = "inbox_reminder"
+	ScheduleActionShoppingList  ScheduleAction = "shopping_list"
+	ScheduleActionReadingList   ScheduleAction = "reading_list"
+	ScheduleActionMessage       ScheduleAction = "message"
+)
+
+var defaultSchedules = []*ScheduleConfig{
+	{
+		Name:   "inbox",
+		Cron:   "0 6 * * *",
+		Action: ScheduleActionInboxReminder,
+	},
+}
+
+type ScheduleConfig struct {
+	Name string `yaml:"name"`
+	// Cron is a standard five-field cron expression.
+	Cron string `yaml:"cron"`
+	// Timezone overrides the server timezone for this schedule.
+	Timezone string         `yaml:"timezone"`
+	Action   ScheduleAction `yaml:"action"`
+	// Message is the text sent by the "message" action.
+	Message string `yaml:"message"`
+	// UserIDs limits recipients, by default every editor and owner is notified.
+	UserIDs []int64 `yaml:"user_ids"`
+}
+
+// Spec returns the cron spec with the timezone applied.
+func (s *ScheduleConfig) Spec() string {
+	if s.Timezone == "" {
+		return s.Cron
+	}
+
+	return fmt.Sprintf("CRON_TZ=%s %s", s.Timezone, s.Cron)
+}
+
+func validateSchedulesConfig(config *Config) error {
+	if config.Schedules == nil {
+		config.Schedules = defaultSchedules
+	}
+
+	for i, schedule := range config.Schedules {
+		if err := validateScheduleConfig(schedule, config.Server); err != nil {
+			return fmt.Errorf("validate schedule #%d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func validateScheduleConfig(config *ScheduleConfig, server *ServerConfig) error {
+	switch {
+	case config == nil:
+		return xerrors.New("schedule is empty")
+	case config.Cron == "":
+		return xerrors.New("\"cron\" is required")
+	case config.Action == "":
+		return xerrors.New("\"action\" is required")
+	case config.Action == ScheduleActionMessage && config.Message == "":
+		return xerrors.New("\"message\" is required for \"message\" action")
+	}
+
+	switch config.Action {
+	case ScheduleActionInboxReminder, ScheduleActionShoppingList, ScheduleActionReadingList, ScheduleActionMessage:
+	default:
+		return fmt.Errorf("unknown \"action\" [action = %q]", config.Action)
+	}
+
+	if config.Timezone != "" {
+		if _, err := time.LoadLocation(config.Timezone); err != nil {
+			return fmt.Errorf("load \"timezone\" [timezone = %q]: %w", config.Timezone, err)
+		}
+	}
+
+	if _, err := cron.ParseStandard(config.Spec()); err != nil {
+		return fmt.Errorf("parse \"cron\" [cron = %q]: %w", config.Cron, err)
+	}
+
+	for _, id := range config.UserIDs {
+		if !hasUser(server.Users, id) {
+			return fmt.Errorf("unknown user in \"user_ids\" [id = %d]", id)
+		}
+	}
+
+	return nil
+}
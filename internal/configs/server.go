@@ -1,23 +1,107 @@
 package configs
 
-import "golang.org/x/xerrors"
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"golang.org/x/xerrors"
+)
+
+const defaultTimezone = "Europe/Moscow"
 
 type ServerConfig struct {
-	Host                 string `yaml:"host"`
-	Port                 string `yaml:"port"`
-	UserID               int64  `yaml:"user_id"`
-	ObsidianAbsolutePath string `yaml:"obsidian_absolute_path"`
+	Host string `yaml:"host"`
+	// Port serves the telegram webhook, it is required in webhook mode only.
+	Port string `yaml:"port"`
+	// UserID is a shorthand for a single owner, prefer Users.
+	UserID               int64         `yaml:"user_id"`
+	ObsidianAbsolutePath string        `yaml:"obsidian_absolute_path"`
+	Users                []*UserConfig `yaml:"users"`
+	// Timezone is an IANA name used for timestamps, daily notes and schedules.
+	Timezone string         `yaml:"timezone"`
+	Location *time.Location `yaml:"-"`
+	// LockDir enables advisory locks of edited notes, it must be outside the vault.
+	LockDir string `yaml:"lock_dir"`
+}
+
+type UserConfig struct {
+	ID   int64       `yaml:"id"`
+	Name string      `yaml:"name"`
+	Role models.Role `yaml:"role"`
+	// VaultPath overrides ObsidianAbsolutePath for this user.
+	VaultPath string `yaml:"vault_path"`
 }
 
 func validateServerConfig(config *ServerConfig) error {
 	switch {
-	case config.Port == "":
-		return xerrors.New("\"port\" is required")
-	case config.UserID == 0:
-		return xerrors.New("\"user_id\" is required")
+	case config.UserID == 0 && len(config.Users) == 0:
+		return xerrors.New("\"users\" is required")
 	case config.ObsidianAbsolutePath == "":
 		return xerrors.New("\"obsidian_absolute_path\" is required")
 	}
 
+	if config.Timezone == "" {
+		config.Timezone = defaultTimezone
+	}
+
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return fmt.Errorf("load \"timezone\" [timezone = %q]: %w", config.Timezone, err)
+	}
+	config.Location = location
+
+	if config.LockDir != "" {
+		if err := os.MkdirAll(config.LockDir, 0755); err != nil {
+			return fmt.Errorf("create \"lock_dir\" [path = %q]: %w", config.LockDir, err)
+		}
+	}
+
+	if config.UserID != 0 && !hasUser(config.Users, config.UserID) {
+		config.Users = append([]*UserConfig{{ID: config.UserID, Role: models.RoleOwner}}, config.Users...)
+	}
+
+	seen := make(map[int64]struct{}, len(config.Users))
+	for i, user := range config.Users {
+		if err := validateUserConfig(user); err != nil {
+			return fmt.Errorf("validate user #%d: %w", i+1, err)
+		}
+
+		if _, ok := seen[user.ID]; ok {
+			return fmt.Errorf("duplicate user [id = %d]", user.ID)
+		}
+		seen[user.ID] = struct{}{}
+
+		if user.VaultPath == "" {
+			user.VaultPath = config.ObsidianAbsolutePath
+		}
+	}
+
 	return nil
 }
+
+func validateUserConfig(config *UserConfig) error {
+	switch {
+	case config == nil:
+		return xerrors.New("user is empty")
+	case config.ID == 0:
+		return xerrors.New("\"id\" is required")
+	case config.Role == "":
+		return xerrors.New("\"role\" is required")
+	case !config.Role.Valid():
+		return fmt.Errorf("unknown \"role\" [role = %q]", config.Role)
+	}
+
+	return nil
+}
+
+func hasUser(users []*UserConfig, id int64) bool {
+	for _, user := range users {
+		if user != nil && user.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package configs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+)
+
+// inboxTag is the tag inbox notes are found by, sorted notes lose it.
+const inboxTag = "inbox"
+
+// validateVaultConfig fills omitted paths with defaults and rejects paths
+// pointing outside the vault.
+func validateVaultConfig(config *models.VaultConfig) error {
+	defaults := models.DefaultVaultConfig()
+
+	paths := []struct {
+		name  string
+		value *string
+		def   string
+	}{
+		{"shopping_list", &config.ShoppingList, defaults.ShoppingList},
+		{"wish_list", &config.WishList, defaults.WishList},
+		{"inbox_template", &config.InboxTemplate, defaults.InboxTemplate},
+		{"timestamps_dir", &config.TimestampsDir, defaults.TimestampsDir},
+		{"attachments_dir", &config.AttachmentsDir, defaults.AttachmentsDir},
+		{"books_dir", &config.BooksDir, defaults.BooksDir},
+		{"films_dir", &config.FilmsDir, defaults.FilmsDir},
+		{"tasks_list", &config.TasksList, defaults.TasksList},
+		{"trash_dir", &config.TrashDir, defaults.TrashDir},
+	}
+
+	for _, path := range paths {
+		if *path.value == "" {
+			*path.value = path.def
+		}
+
+		if !filepath.IsLocal(*path.value) {
+			return fmt.Errorf("%q must be a relative path inside the vault [path = %q]", path.name, *path.value)
+		}
+	}
+
+	optionalPaths := []struct {
+		name  string
+		value string
+	}{
+		{"shopping_categories", config.ShoppingCategories},
+		{"book_template", config.BookTemplate},
+		{"film_template", config.FilmTemplate},
+	}
+
+	for _, path := range optionalPaths {
+		if path.value != "" && !filepath.IsLocal(path.value) {
+			return fmt.Errorf("%q must be a relative path inside the vault [path = %q]", path.name, path.value)
+		}
+	}
+
+	if config.InboxExcluded == nil {
+		config.InboxExcluded = defaults.InboxExcluded
+	}
+
+	if config.InboxExcludedDirs == nil {
+		config.InboxExcludedDirs = defaults.InboxExcludedDirs
+	}
+
+	dirLists := []struct {
+		name  string
+		value []string
+	}{
+		{"inbox_excluded_dirs", config.InboxExcludedDirs},
+		{"triage_folders", config.TriageFolders},
+	}
+
+	for _, list := range dirLists {
+		for i, path := range list.value {
+			if !filepath.IsLocal(path) {
+				return fmt.Errorf("%q must be relative paths inside the vault [path = %q]", list.name, path)
+			}
+			list.value[i] = filepath.Clean(path)
+		}
+	}
+
+	for _, tag := range config.TriageTags {
+		if strings.TrimPrefix(tag, "#") == inboxTag || strings.ContainsAny(tag, " \t") {
+			return fmt.Errorf("\"triage_tags\" must be tags other than inbox [tag = %q]", tag)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Forward is the source of a forwarded message.
+type Forward struct {
+	// Chat is the title of the channel or group the message comes from,
+	// empty for messages forwarded from users.
+	Chat   string
+	Author string
+	Date   time.Time
+	// Link points to the original message, it is known for channel posts only.
+	Link string
+}
@@ -0,0 +1,11 @@
+package models
+
+type InboxNote struct {
+	// ID identifies the note by the hash of its path, so a stale keyboard
+	// can't act on another note.
+	ID    string
+	Title string
+	Path  string
+	// Preview is the first lines of the note body.
+	Preview string
+}
@@ -0,0 +1,9 @@
+package models
+
+type Location struct {
+	Lat float64
+	Lon float64
+	// Venue and Address are set for venues only.
+	Venue   string
+	Address string
+}
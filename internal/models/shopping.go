@@ -0,0 +1,13 @@
+package models
+
+type ShoppingItem struct {
+	// ID identifies the item by its position and text, so a stale keyboard
+	// can't toggle another item after the list has changed.
+	ID      string
+	Text    string
+	Checked bool
+	// Section is the heading the item is listed under.
+	Section string
+	// Depth is the nesting level of the item, zero for top-level items.
+	Depth int
+}
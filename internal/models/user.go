@@ -0,0 +1,45 @@
+// Package models contains entities shared between application layers.
+package models
+
+import "context"
+
+type Role string
+
+const (
+	RoleReadOnly Role = "read-only"
+	RoleEditor   Role = "editor"
+	RoleOwner    Role = "owner"
+)
+
+var roleLevels = map[Role]int{
+	RoleReadOnly: 1,
+	RoleEditor:   2,
+	RoleOwner:    3,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleLevels[r]
+	return ok
+}
+
+// Allows reports whether the role grants at least the required one.
+func (r Role) Allows(required Role) bool {
+	return roleLevels[r] >= roleLevels[required]
+}
+
+type User struct {
+	ID   int64
+	Name string
+	Role Role
+}
+
+type userContextKey struct{}
+
+func ContextWithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+func UserFromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(*User)
+	return user, ok && user != nil
+}
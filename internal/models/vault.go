@@ -0,0 +1,51 @@
+package models
+
+// VaultConfig maps logical lists, directories and templates to paths
+// relative to the vault root.
+type VaultConfig struct {
+	ShoppingList  string `yaml:"shopping_list"`
+	WishList      string `yaml:"wish_list"`
+	InboxTemplate string `yaml:"inbox_template"`
+	// BookTemplate and FilmTemplate are optional, built-in templates are
+	// used when the notes don't exist.
+	BookTemplate  string `yaml:"book_template"`
+	FilmTemplate  string `yaml:"film_template"`
+	TimestampsDir string `yaml:"timestamps_dir"`
+	BooksDir      string `yaml:"books_dir"`
+	FilmsDir      string `yaml:"films_dir"`
+	// AttachmentsDir is the folder files sent to the bot are saved to, it is
+	// created on the first file.
+	AttachmentsDir string `yaml:"attachments_dir"`
+	// ShoppingCategories is an optional note mapping keywords to sections
+	// of the shopping list.
+	ShoppingCategories string `yaml:"shopping_categories"`
+	// InboxExcluded lists notes which are never reported as inbox items.
+	InboxExcluded []string `yaml:"inbox_excluded"`
+	// InboxExcludedDirs lists folders which are not searched for inbox notes.
+	InboxExcludedDirs []string `yaml:"inbox_excluded_dirs"`
+	// TasksList is the note inbox notes are converted into tasks of, it is
+	// created on the first task.
+	TasksList string `yaml:"tasks_list"`
+	// TrashDir is the folder deleted inbox notes are moved to.
+	TrashDir string `yaml:"trash_dir"`
+	// TriageFolders and TriageTags are offered when sorting the inbox.
+	TriageFolders []string `yaml:"triage_folders"`
+	TriageTags    []string `yaml:"triage_tags"`
+}
+
+func DefaultVaultConfig() *VaultConfig {
+	return &VaultConfig{
+		ShoppingList:       "Shopping List.md",
+		WishList:           "Wish List.md",
+		InboxTemplate:      "Bins/Templates/Inbox.md",
+		TimestampsDir:      "Timestamps",
+		AttachmentsDir:     "Attachments",
+		BooksDir:           "Books",
+		FilmsDir:           "Films",
+		ShoppingCategories: "Shopping Categories.md",
+		InboxExcluded:      []string{"README.md", "Inbox Notes.md"},
+		InboxExcludedDirs:  []string{"Bins/Templates"},
+		TasksList:          "Tasks.md",
+		TrashDir:           ".trash",
+	}
+}
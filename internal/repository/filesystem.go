@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,57 +9,60 @@ import (
 
 type fileSystem struct {
 	AbsolutePath string
+	// LockDir enables advisory file locks, empty disables them.
+	LockDir string
+
+	locks pathLocks
 }
 
-func New(absolutePath string) *fileSystem {
+func New(absolutePath, lockDir string) *fileSystem {
 	return &fileSystem{
 		AbsolutePath: absolutePath,
+		LockDir:      lockDir,
 	}
 }
 
-func (fs *fileSystem) joinWithAbsolutePath(path string) string {
-	return filepath.Join(fs.AbsolutePath, path)
-}
-
-func (fs *fileSystem) OpenFile(fp string) (*os.File, error) {
-	fp = fs.joinWithAbsolutePath(fp)
-
-	file, err := os.Open(fp)
+func (fs *fileSystem) FileExist(fp string) (bool, error) {
+	fp, err := fs.joinWithAbsolutePath(fp)
 	if err != nil {
-		return nil, fmt.Errorf("open file [filepath = %q]: %w", fp, err)
+		return false, err
 	}
 
-	return file, nil
-}
-
-func (fs *fileSystem) CreateFile(fp string) (*os.File, error) {
-	fp = fs.joinWithAbsolutePath(fp)
-
-	file, err := os.Create(fp)
+	info, err := os.Stat(fp)
 	if err != nil {
-		return nil, fmt.Errorf("create file [filepath = %q]: %w", fp, err)
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("stat file [filepath = %q]: %w", fp, err)
 	}
 
-	return file, nil
+	return !info.IsDir(), nil
 }
 
-func (fs *fileSystem) FileExist(fp string) (bool, error) {
-	fp = fs.joinWithAbsolutePath(fp)
+func (fs *fileSystem) DirExist(path string) (bool, error) {
+	path, err := fs.joinWithAbsolutePath(path)
+	if err != nil {
+		return false, err
+	}
 
-	info, err := os.Stat(fp)
+	info, err := os.Stat(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
 		}
 
-		return false, fmt.Errorf("stat file [filepath = %q]: %w", fp, err)
+		return false, fmt.Errorf("stat dir [path = %q]: %w", path, err)
 	}
 
-	return !info.IsDir(), nil
+	return info.IsDir(), nil
 }
 
 func (fs *fileSystem) ReadFromFile(fp string) (string, error) {
-	fp = fs.joinWithAbsolutePath(fp)
+	fp, err := fs.joinWithAbsolutePath(fp)
+	if err != nil {
+		return "", err
+	}
 
 	data, err := os.ReadFile(fp)
 	if err != nil {
@@ -69,7 +73,10 @@ func (fs *fileSystem) ReadFromFile(fp string) (string, error) {
 }
 
 func (fs *fileSystem) ReadDir(path string) ([]os.DirEntry, error) {
-	path = fs.joinWithAbsolutePath(path)
+	path, err := fs.joinWithAbsolutePath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	entities, err := os.ReadDir(path)
 	if err != nil {
@@ -79,24 +86,174 @@ func (fs *fileSystem) ReadDir(path string) ([]os.DirEntry, error) {
 	return entities, nil
 }
 
-func (fs *fileSystem) AppendToFile(fp string, data string) error {
-	fp = fs.joinWithAbsolutePath(fp)
+// CreateDir creates the directory with its missing parents.
+func (fs *fileSystem) CreateDir(path string) error {
+	path, err := fs.joinWithAbsolutePath(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("create dir [path = %q]: %w", path, err)
+	}
+
+	return nil
+}
+
+func (fs *fileSystem) RemoveFile(fp string) error {
+	fp, err := fs.joinWithAbsolutePath(fp)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := fs.lock(fp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(fp); err != nil {
+		return fmt.Errorf("remove file [filepath = %q]: %w", fp, err)
+	}
+
+	return nil
+}
 
-	file, err := os.OpenFile(fp, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// WriteNewFile creates the file with data, it fails with an error matching
+// os.ErrExist when the file already exists, so nothing is overwritten.
+func (fs *fileSystem) WriteNewFile(fp string, data string) error {
+	fp, err := fs.joinWithAbsolutePath(fp)
 	if err != nil {
-		return fmt.Errorf("failed to open file [filepath = %q]: %w", fp, err)
+		return err
+	}
+
+	unlock, err := fs.lock(fp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeExclusive(fp, data)
+}
+
+// UpsertFile is UpdateFile for files which may not exist yet: update gets
+// empty data and exist set to false for a missing file, which is created
+// without overwriting one created by someone else in the meantime.
+func (fs *fileSystem) UpsertFile(fp string, update func(data string, exist bool) (string, error)) error {
+	fp, err := fs.joinWithAbsolutePath(fp)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := fs.lock(fp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for {
+		data, err := os.ReadFile(fp)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("read file [filepath = %q]: %w", fp, err)
+		}
+		exist := err == nil
+
+		updated, err := update(string(data), exist)
+		if err != nil {
+			return err
+		}
+
+		if exist {
+			return writeAtomic(fp, updated)
+		}
+
+		// the file may be created by another program, e.g. Obsidian Sync,
+		// then it is updated instead
+		err = writeExclusive(fp, updated)
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+	}
+}
+
+// UpdateFile replaces the content of the file with the result of update,
+// holding the file lock between reading and writing.
+func (fs *fileSystem) UpdateFile(fp string, update func(data string) (string, error)) error {
+	fp, err := fs.joinWithAbsolutePath(fp)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := fs.lock(fp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(fp)
+	if err != nil {
+		return fmt.Errorf("read file [filepath = %q]: %w", fp, err)
+	}
+
+	updated, err := update(string(data))
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(fp, updated)
+}
+
+// writeExclusive creates the file with data unless it exists.
+func writeExclusive(fp string, data string) error {
+	file, err := os.OpenFile(fp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("create file [filepath = %q]: %w", fp, err)
 	}
-	defer file.Close()
 
 	if _, err := file.WriteString(data); err != nil {
-		return fmt.Errorf("write string [filepath = %q]: %w", fp, err)
+		file.Close()
+		os.Remove(fp)
+		return fmt.Errorf("write file [filepath = %q]: %w", fp, err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(fp)
+		return fmt.Errorf("close file [filepath = %q]: %w", fp, err)
 	}
 
 	return nil
 }
 
-func (fs *fileSystem) WriteToFile(fp string, data string) error {
-	fp = fs.joinWithAbsolutePath(fp)
+// writeAtomic writes data to a temporary file next to fp and renames it,
+// so readers never observe a partially written file.
+func writeAtomic(fp string, data string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(fp), "."+filepath.Base(fp)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file [filepath = %q]: %w", fp, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file [filepath = %q]: %w", tmp.Name(), err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file [filepath = %q]: %w", tmp.Name(), err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file [filepath = %q]: %w", tmp.Name(), err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("chmod temp file [filepath = %q]: %w", tmp.Name(), err)
+	}
 
-	return os.WriteFile(fp, []byte(data), 0644)
+	if err := os.Rename(tmp.Name(), fp); err != nil {
+		return fmt.Errorf("rename temp file [filepath = %q]: %w", fp, err)
+	}
+
+	return nil
 }
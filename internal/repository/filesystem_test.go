@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteNewFile(t *testing.T) {
+	fs := New(t.TempDir(), "")
+
+	if err := fs.WriteNewFile("note.md", "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.WriteNewFile("note.md", "second"); !errors.Is(err, os.ErrExist) {
+		t.Errorf("WriteNewFile() of an existing file error = %v, want %v", err, os.ErrExist)
+	}
+
+	if data, _ := fs.ReadFromFile("note.md"); data != "first" {
+		t.Errorf("note = %q, want it not overwritten", data)
+	}
+}
+
+func TestUpsertFileConcurrently(t *testing.T) {
+	dir, lockDir := t.TempDir(), t.TempDir()
+
+	// instances of the bot sharing the vault and the lock dir
+	instances := []*fileSystem{New(dir, lockDir), New(dir, lockDir)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			err := instances[i%2].UpsertFile("timestamps.md", func(data string, exist bool) (string, error) {
+				return data + fmt.Sprintf("entry %d\n", i), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := instances[0].ReadFromFile("timestamps.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(data, "entry "); got != 20 {
+		t.Errorf("timestamps have %d entries, want 20:\n%s", got, data)
+	}
+
+	for _, fs := range instances {
+		if n := len(fs.locks.locks); n != 0 {
+			t.Errorf("%d locks are kept after the edits", n)
+		}
+	}
+}
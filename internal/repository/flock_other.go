@@ -0,0 +1,14 @@
+//go:build !unix
+
+package repository
+
+import "os"
+
+// Advisory locks are not supported, edits are serialized inside the process only.
+func flock(*os.File) error {
+	return nil
+}
+
+func funlock(*os.File) error {
+	return nil
+}
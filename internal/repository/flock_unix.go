@@ -0,0 +1,16 @@
+//go:build unix
+
+package repository
+
+import (
+	"os"
+	"syscall"
+)
+
+func flock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func funlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
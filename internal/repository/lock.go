@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// pathLocks serializes edits of the same file inside the process. Entries
+// are counted and dropped by the last holder, so the map doesn't grow with
+// every file ever edited.
+type pathLocks struct {
+	mu    sync.Mutex
+	locks map[string]*pathLock
+}
+
+type pathLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks fp and returns the func unlocking it.
+func (l *pathLocks) lock(fp string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*pathLock)
+	}
+
+	lock, ok := l.locks[fp]
+	if !ok {
+		lock = &pathLock{}
+		l.locks[fp] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, fp)
+		}
+	}
+}
+
+// lock acquires the process lock for the resolved path fp and, when lock
+// dir is configured, an advisory file lock shared with other processes.
+func (fs *fileSystem) lock(fp string) (func(), error) {
+	unlock := fs.locks.lock(fp)
+
+	if fs.LockDir == "" {
+		return unlock, nil
+	}
+
+	// Lock files live outside the vault, so they are never synced and
+	// survive the rename of the locked file.
+	sum := sha1.Sum([]byte(fp))
+	lockPath := filepath.Join(fs.LockDir, hex.EncodeToString(sum[:])+".lock")
+
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("open lock file [filepath = %q]: %w", lockPath, err)
+	}
+
+	if err := flock(file); err != nil {
+		file.Close()
+		unlock()
+		return nil, fmt.Errorf("lock file [filepath = %q]: %w", lockPath, err)
+	}
+
+	return func() {
+		_ = funlock(file)
+		file.Close()
+		unlock()
+	}, nil
+}
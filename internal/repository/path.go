@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathEscapeError is returned when a path resolves outside the vault.
+type PathEscapeError struct {
+	Path string
+}
+
+func (e *PathEscapeError) Error() string {
+	return fmt.Sprintf("path escapes the vault [path = %q]", e.Path)
+}
+
+// joinWithAbsolutePath joins path with the vault root and makes sure the
+// result, with symlinks resolved, stays inside the vault. The resolved path
+// is returned, so a symlink swapped after the check is not followed.
+func (fs *fileSystem) joinWithAbsolutePath(path string) (string, error) {
+	root, err := filepath.EvalSymlinks(fs.AbsolutePath)
+	if err != nil {
+		return "", fmt.Errorf("resolve vault root [path = %q]: %w", fs.AbsolutePath, err)
+	}
+
+	fp := filepath.Join(fs.AbsolutePath, path)
+	if !isWithin(filepath.Clean(fs.AbsolutePath), fp) {
+		return "", &PathEscapeError{Path: path}
+	}
+
+	resolved, err := resolveExisting(fp)
+	if err != nil {
+		return "", fmt.Errorf("resolve path [path = %q]: %w", path, err)
+	}
+
+	if !isWithin(root, resolved) {
+		return "", &PathEscapeError{Path: path}
+	}
+
+	return resolved, nil
+}
+
+// resolveExisting evaluates symlinks of the longest existing prefix of fp,
+// so paths of files which are about to be created are checked as well.
+func resolveExisting(fp string) (string, error) {
+	var rest []string
+	for {
+		resolved, err := filepath.EvalSymlinks(fp)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...), nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(fp)
+		if parent == fp {
+			return "", err
+		}
+
+		rest = append([]string{filepath.Base(fp)}, rest...)
+		fp = parent
+	}
+}
+
+func isWithin(root, fp string) bool {
+	rel, err := filepath.Rel(root, fp)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJoinWithAbsolutePath(t *testing.T) {
+	dir := t.TempDir()
+	vault := filepath.Join(dir, "vault")
+	outside := filepath.Join(dir, "outside")
+
+	for _, path := range []string{filepath.Join(vault, "Notes"), outside} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	links := map[string]string{
+		filepath.Join(vault, "out"):    outside,
+		filepath.Join(vault, "in"):     filepath.Join(vault, "Notes"),
+		filepath.Join(dir, "shortcut"): vault,
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks are not supported: %v", err)
+		}
+	}
+
+	// the temp dir may be behind a symlink itself
+	resolvedVault, err := filepath.EvalSymlinks(vault)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		resolved string
+		escapes  bool
+	}{
+		{path: "Notes/Idea.md", resolved: "Notes/Idea.md"},
+		{path: "New Folder/New.md", resolved: "New Folder/New.md"},
+		{path: "in/Idea.md", resolved: "Notes/Idea.md"},
+		{path: "Notes/../Idea.md", resolved: "Idea.md"},
+		{path: "/Idea.md", resolved: "Idea.md"},
+		{path: "../outside/secret.md", escapes: true},
+		{path: "Notes/../../outside/secret.md", escapes: true},
+		{path: "out/secret.md", escapes: true},
+		{path: "out/New Folder/new.md", escapes: true},
+	}
+
+	for _, root := range []string{vault, filepath.Join(dir, "shortcut")} {
+		fs := New(root, "")
+
+		for _, tt := range tests {
+			fp, err := fs.joinWithAbsolutePath(tt.path)
+
+			var escapeErr *PathEscapeError
+			if errors.As(err, &escapeErr) != tt.escapes {
+				t.Errorf("joinWithAbsolutePath(%q) [root = %q] error = %v, escapes %v", tt.path, root, err, tt.escapes)
+			}
+
+			if tt.resolved != "" && fp != filepath.Join(resolvedVault, tt.resolved) {
+				t.Errorf("joinWithAbsolutePath(%q) [root = %q] = %q, want %q resolved", tt.path, root, fp, tt.resolved)
+			}
+		}
+	}
+}
@@ -2,17 +2,23 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
+	"github.com/r-mol/ObsidianBot/internal/models"
 	log "github.com/sirupsen/logrus"
 	tb "gopkg.in/telebot.v3"
 )
 
 type ObsidianUsecase interface {
 	ParseMessage(ctx context.Context, msg string) (string, error)
+	SaveAttachment(ctx context.Context, name string, file io.Reader, caption string) (string, error)
+	SaveVoice(ctx context.Context, file io.Reader, caption string) (string, error)
+	SaveForwarded(ctx context.Context, forward models.Forward, text string) (string, error)
 	CreateNewNoteToInbox(ctx context.Context, msg string) (string, error)
 	AddAction(ctx context.Context, msg string) (string, error)
 	GetWishList(ctx context.Context, msg string) (string, error)
@@ -20,23 +26,57 @@ type ObsidianUsecase interface {
 	AddItemsToShoppingList(ctx context.Context, msg string) (string, error)
 	ClearShoppingList(ctx context.Context, msg string) (string, error)
 	RemoveItemsFromShoppingList(ctx context.Context, msg string) (string, error)
-	RememberAboutInbox(ctx context.Context) string
+	GetShoppingItems(ctx context.Context) ([]models.ShoppingItem, error)
+	ToggleShoppingItem(ctx context.Context, id string) (string, error)
+	RemoveCheckedShoppingItems(ctx context.Context, msg string) (string, error)
+	GetInboxItems(ctx context.Context, msg string) (string, error)
+	GetInboxNotes(ctx context.Context) ([]models.InboxNote, error)
+	GetInboxNote(ctx context.Context, id string) (models.InboxNote, error)
+	TriageOptions() (folders, tags []string)
+	MoveInboxNote(ctx context.Context, id string, folder int) (string, error)
+	RetagInboxNote(ctx context.Context, id string, tag int) (string, error)
+	InboxNoteToTask(ctx context.Context, id string) (string, error)
+	InboxNoteToTimestamps(ctx context.Context, id string) (string, error)
+	DeleteInboxNote(ctx context.Context, id string) (string, error)
+	AddLocation(ctx context.Context, location models.Location) (string, error)
+	SetInboxNoteLocation(ctx context.Context, id string, location models.Location) (string, error)
+	LatestInboxNote(ctx context.Context) (*models.InboxNote, error)
+	LastChangeID(ctx context.Context) int64
+	UndoChange(ctx context.Context, id int64) (string, error)
 }
 
 type bot struct {
 	ObsidianUsecase ObsidianUsecase
-	UserID          int64
+	Users           map[int64]*models.User
 }
 
-func NewBot(obsidianUsecase ObsidianUsecase, userID int64) *bot {
+func NewBot(obsidianUsecase ObsidianUsecase, users []*models.User) *bot {
+	usersByID := make(map[int64]*models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
 	return &bot{
 		ObsidianUsecase: obsidianUsecase,
-		UserID:          userID,
+		Users:           usersByID,
 	}
 }
 
 func (br *bot) TextMessageHandler(ctx context.Context, b *tb.Bot) {
-	b.Handle(tb.OnText, func(c tb.Context) error {
+	b.Handle(tb.OnText, br.message(ctx, b, "message", func(ctx context.Context, c tb.Context) (string, error) {
+		if msg := c.Message(); msg.OriginalUnixtime != 0 {
+			return br.ObsidianUsecase.SaveForwarded(ctx, forwardOf(msg), c.Text())
+		}
+
+		return br.ObsidianUsecase.ParseMessage(ctx, c.Text())
+	}))
+}
+
+// message wraps the handler of an incoming message: it checks that the user
+// is an editor, puts the user into the context and replies with the result
+// and the "Undo" button when the handler changed the vault.
+func (br *bot) message(ctx context.Context, b *tb.Bot, kind string, handle func(ctx context.Context, c tb.Context) (string, error)) tb.HandlerFunc {
+	return func(c tb.Context) error {
 		ctx, cancel := context.WithCancel(ctx)
 		defer cancel()
 
@@ -46,34 +86,44 @@ func (br *bot) TextMessageHandler(ctx context.Context, b *tb.Bot) {
 			return fmt.Errorf("nil sender for updateID=%d", c.Update().ID)
 		}
 
-		log.Infof("receive tg message: updateID=%d userID=%d username=%s text=%s",
-			c.Update().ID, user.ID, user.Username, c.Text())
+		log.Infof("receive tg %s: updateID=%d userID=%d username=%s text=%s",
+			kind, c.Update().ID, user.ID, user.Username, c.Text())
 
 		var userFriendlyMessage string
 		var err error
-		if br.checkUser(user.ID) {
-			userFriendlyMessage, err = br.ObsidianUsecase.ParseMessage(ctx, c.Text())
+		var markup *tb.ReplyMarkup
+		if u, ok := br.checkUser(user.ID, models.RoleEditor); ok {
+			ctx = models.ContextWithUser(ctx, u)
+			previousChangeID := br.ObsidianUsecase.LastChangeID(ctx)
+
+			userFriendlyMessage, err = handle(ctx, c)
 			if err != nil {
 				log.Errorf("Message proccess error from handler: %v", err)
 				userFriendlyMessage = fmt.Errorf("**Error occurred in proccessing message.**\n\n%w", err).Error()
+			} else {
+				markup = br.undoMarkup(ctx, previousChangeID)
 			}
 		} else {
-			userFriendlyMessage = fmt.Sprintf("**You are not allowed to use this bot.**")
+			userFriendlyMessage = br.notAllowedMessage(user.ID)
 		}
 
-		_, err = b.Send(c.Sender(), userFriendlyMessage, tb.ModeMarkdown)
+		err = send(b, c.Sender(), userFriendlyMessage, markup)
 		if err != nil {
 			return fmt.Errorf("send message: %w", err)
 		}
 
 		return nil
-	})
+	}
 }
 
 type Command struct {
-	DescRu  string
-	DescEn  string
+	DescRu string
+	DescEn string
+	// Role is the minimal role required to run the command.
+	Role    models.Role
 	Handler func(ctx context.Context, msg string) (string, error)
+	// Keyboard optionally attaches an inline keyboard to the reply.
+	Keyboard func(ctx context.Context) (*tb.ReplyMarkup, error)
 }
 
 func (br *bot) SetMenu(ctx context.Context, bot *tb.Bot, menu map[string]Command) error {
@@ -105,17 +155,29 @@ func (br *bot) SetMenu(ctx context.Context, bot *tb.Bot, menu map[string]Command
 
 			var userFriendlyMessage string
 			var err error
-			if br.checkUser(user.ID) {
+			var markup *tb.ReplyMarkup
+			if u, ok := br.checkUser(user.ID, info.Role); ok {
+				ctx = models.ContextWithUser(ctx, u)
+				previousChangeID := br.ObsidianUsecase.LastChangeID(ctx)
+
 				userFriendlyMessage, err = info.Handler(ctx, c.Text())
 				if err != nil {
 					log.Errorf("command %q get error from handler: %v", cmd, err)
 					userFriendlyMessage = fmt.Errorf("**Error occurred in command %q.**\n\n%w", cmd, err).Error()
+				} else {
+					markup = br.undoMarkup(ctx, previousChangeID)
+					if markup == nil && info.Keyboard != nil {
+						markup, err = info.Keyboard(ctx)
+						if err != nil {
+							log.Errorf("command %q get error from keyboard: %v", cmd, err)
+						}
+					}
 				}
 			} else {
-				userFriendlyMessage = fmt.Sprintf("**You are not allowed to use this bot.**")
+				userFriendlyMessage = br.notAllowedMessage(user.ID)
 			}
 
-			_, err = bot.Send(c.Sender(), userFriendlyMessage, tb.ModeMarkdown)
+			err = send(bot, c.Sender(), userFriendlyMessage, markup)
 			if err != nil {
 				return fmt.Errorf("send message: %w", err)
 			}
@@ -137,17 +199,61 @@ func (br *bot) SetMenu(ctx context.Context, bot *tb.Bot, menu map[string]Command
 	return nil
 }
 
-func (br *bot) NotifyUser(ctx context.Context, b *tb.Bot) error {
-	msg := br.ObsidianUsecase.RememberAboutInbox(ctx)
+// NotifyUsers sends the handler result to the given users, or to every
+// user who can edit the vault when userIDs is empty.
+func (br *bot) NotifyUsers(ctx context.Context, b *tb.Bot, userIDs []int64, handler func(ctx context.Context, msg string) (string, error)) error {
+	var errs []error
+	for _, user := range br.recipients(userIDs) {
+		msg, err := handler(models.ContextWithUser(ctx, user), "")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("prepare message [userID = %d]: %w", user.ID, err))
+			continue
+		}
 
-	_, err := b.Send(&tb.User{ID: br.UserID}, msg, tb.ModeMarkdown)
-	if err != nil {
-		return fmt.Errorf("send message: %w", err)
+		err = send(b, &tb.User{ID: user.ID}, msg, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("send message [userID = %d]: %w", user.ID, err))
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func (br *bot) checkUser(userID int64) bool {
-	return br.UserID == userID
+func (br *bot) recipients(userIDs []int64) []*models.User {
+	var users []*models.User
+	if len(userIDs) == 0 {
+		for _, user := range br.Users {
+			if user.Role.Allows(models.RoleEditor) {
+				users = append(users, user)
+			}
+		}
+
+		return users
+	}
+
+	for _, id := range userIDs {
+		if user, ok := br.Users[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
+// checkUser returns the configured user if its role grants the required one.
+func (br *bot) checkUser(userID int64, required models.Role) (*models.User, bool) {
+	user, ok := br.Users[userID]
+	if !ok || !user.Role.Allows(required) {
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (br *bot) notAllowedMessage(userID int64) string {
+	if _, ok := br.Users[userID]; ok {
+		return "**Your role does not allow this action.**"
+	}
+
+	return "**You are not allowed to use this bot.**"
 }
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+)
+
+func testBot() *bot {
+	return NewBot(nil, []*models.User{
+		{ID: 1, Role: models.RoleOwner},
+		{ID: 2, Role: models.RoleEditor},
+		{ID: 3, Role: models.RoleReadOnly},
+	})
+}
+
+func TestCheckUser(t *testing.T) {
+	br := testBot()
+
+	tests := []struct {
+		name     string
+		userID   int64
+		required models.Role
+		want     bool
+	}{
+		{name: "owner edits", userID: 1, required: models.RoleEditor, want: true},
+		{name: "editor edits", userID: 2, required: models.RoleEditor, want: true},
+		{name: "editor is not owner", userID: 2, required: models.RoleOwner},
+		{name: "read-only reads", userID: 3, required: models.RoleReadOnly, want: true},
+		{name: "read-only doesn't edit", userID: 3, required: models.RoleEditor},
+		{name: "stranger", userID: 4, required: models.RoleReadOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, ok := br.checkUser(tt.userID, tt.required)
+			if ok != tt.want {
+				t.Fatalf("checkUser() ok = %v, want %v", ok, tt.want)
+			}
+
+			if ok && user.ID != tt.userID {
+				t.Errorf("checkUser() = user %d, want %d", user.ID, tt.userID)
+			}
+		})
+	}
+
+	if got := br.notAllowedMessage(3); got != "**Your role does not allow this action.**" {
+		t.Errorf("notAllowedMessage() for a read-only user = %q", got)
+	}
+
+	if got := br.notAllowedMessage(4); got != "**You are not allowed to use this bot.**" {
+		t.Errorf("notAllowedMessage() for a stranger = %q", got)
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	br := testBot()
+
+	tests := []struct {
+		name    string
+		userIDs []int64
+		want    []int64
+	}{
+		{name: "editors by default", want: []int64{1, 2}},
+		{name: "listed", userIDs: []int64{3, 2}, want: []int64{3, 2}},
+		{name: "unknown skipped", userIDs: []int64{4, 1}, want: []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, user := range br.recipients(tt.userIDs) {
+				got = append(got, user.ID)
+			}
+
+			// users of the config are kept in a map
+			if tt.userIDs == nil {
+				slices.Sort(got)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("recipients(%v) = %v, want %v", tt.userIDs, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	log "github.com/sirupsen/logrus"
+	tb "gopkg.in/telebot.v3"
+)
+
+// maxCallbackText is the Telegram limit of the text of a callback answer.
+const maxCallbackText = 200
+
+// callback wraps the action of an inline button: it checks the user role
+// and puts the user into the context of the action.
+func (br *bot) callback(ctx context.Context, unique string, required models.Role, action func(ctx context.Context, c tb.Context) error) tb.HandlerFunc {
+	return func(c tb.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		user := c.Sender()
+
+		if user == nil {
+			return fmt.Errorf("nil sender for updateID=%d", c.Update().ID)
+		}
+
+		log.Infof("receive tg callback: %s updateID=%d userID=%d username=%s data=%s",
+			unique, c.Update().ID, user.ID, user.Username, c.Data())
+
+		u, ok := br.checkUser(user.ID, required)
+		if !ok {
+			return c.Respond(&tb.CallbackResponse{
+				Text:      strings.Trim(br.notAllowedMessage(user.ID), "*"),
+				ShowAlert: true,
+			})
+		}
+
+		return action(models.ContextWithUser(ctx, u), c)
+	}
+}
+
+// callbackText cuts the text to fit into a callback answer, the full text
+// of errors is in the log.
+func callbackText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxCallbackText {
+		return text
+	}
+
+	return string(runes[:maxCallbackText-1]) + "…"
+}
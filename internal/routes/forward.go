@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	tb "gopkg.in/telebot.v3"
+)
+
+// forwardOf returns the source of the forwarded message.
+func forwardOf(msg *tb.Message) models.Forward {
+	forward := models.Forward{
+		Date: time.Unix(int64(msg.OriginalUnixtime), 0),
+	}
+
+	switch {
+	case msg.OriginalSignature != "":
+		forward.Author = msg.OriginalSignature
+	case msg.OriginalSender != nil:
+		forward.Author = userName(msg.OriginalSender)
+	case msg.OriginalSenderName != "":
+		// the user hides the account in forwarded messages
+		forward.Author = msg.OriginalSenderName
+	}
+
+	if chat := msg.OriginalChat; chat != nil {
+		forward.Chat = chat.Title
+		forward.Link = messageLink(chat, msg.OriginalMessageID)
+	}
+
+	return forward
+}
+
+func userName(user *tb.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if user.Username != "" {
+		name = strings.TrimSpace(fmt.Sprintf("%s (@%s)", name, user.Username))
+	}
+
+	return name
+}
+
+// messageLink returns the t.me link of the channel post, private channels
+// are linked by their id and open for members only.
+func messageLink(chat *tb.Chat, messageID int) string {
+	if messageID == 0 {
+		return ""
+	}
+
+	if chat.Username != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", chat.Username, messageID)
+	}
+
+	if id := strconv.FormatInt(chat.ID, 10); strings.HasPrefix(id, "-100") {
+		return fmt.Sprintf("https://t.me/c/%s/%d", strings.TrimPrefix(id, "-100"), messageID)
+	}
+
+	return ""
+}
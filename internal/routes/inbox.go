@@ -0,0 +1,253 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	log "github.com/sirupsen/logrus"
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	uniqueInboxOpen       = "inbox_open"
+	uniqueInboxBack       = "inbox_back"
+	uniqueInboxMoveMenu   = "inbox_move_menu"
+	uniqueInboxMove       = "inbox_move"
+	uniqueInboxRetagMenu  = "inbox_retag_menu"
+	uniqueInboxRetag      = "inbox_retag"
+	uniqueInboxTask       = "inbox_task"
+	uniqueInboxTimestamps = "inbox_timestamps"
+	uniqueInboxDelete     = "inbox_delete"
+	uniqueInboxPage       = "inbox_page"
+)
+
+// inboxPageSize is the number of notes on a page of the inbox keyboard,
+// Telegram limits the number of buttons of a message.
+const inboxPageSize = 20
+
+// InboxMarkup returns an inline keyboard with a button per inbox note,
+// tapping a button opens the note for sorting.
+func (br *bot) InboxMarkup(ctx context.Context) (*tb.ReplyMarkup, error) {
+	return br.inboxMarkup(ctx, 0)
+}
+
+// inboxMarkup returns the page of the inbox keyboard, the notes which don't
+// fit are reached with the buttons under the list.
+func (br *bot) inboxMarkup(ctx context.Context, page int) (*tb.ReplyMarkup, error) {
+	notes, err := br.ObsidianUsecase.GetInboxNotes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get inbox notes: %w", err)
+	}
+
+	if len(notes) == 0 {
+		return nil, nil
+	}
+
+	// the inbox may have shrunk since the page was shown
+	pages := (len(notes) + inboxPageSize - 1) / inboxPageSize
+	page = max(0, min(page, pages-1))
+
+	markup := &tb.ReplyMarkup{}
+	rows := make([]tb.Row, 0, inboxPageSize+1)
+	for _, note := range notes[page*inboxPageSize : min((page+1)*inboxPageSize, len(notes))] {
+		rows = append(rows, markup.Row(markup.Data("📝 "+note.Title, uniqueInboxOpen, note.ID)))
+	}
+
+	var nav tb.Row
+	if page > 0 {
+		nav = append(nav, markup.Data("⬅️ Previous", uniqueInboxPage, strconv.Itoa(page-1)))
+	}
+	if page < pages-1 {
+		nav = append(nav, markup.Data("Next ➡️", uniqueInboxPage, strconv.Itoa(page+1)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	markup.Inline(rows...)
+
+	return markup, nil
+}
+
+// inboxNoteMarkup returns the triage actions of the note.
+func (br *bot) inboxNoteMarkup(id string) *tb.ReplyMarkup {
+	folders, tags := br.ObsidianUsecase.TriageOptions()
+
+	markup := &tb.ReplyMarkup{}
+
+	var sort tb.Row
+	if len(folders) > 0 {
+		sort = append(sort, markup.Data("📁 Move", uniqueInboxMoveMenu, id))
+	}
+	if len(tags) > 0 {
+		sort = append(sort, markup.Data("🏷 Retag", uniqueInboxRetagMenu, id))
+	}
+
+	rows := []tb.Row{
+		markup.Row(markup.Data("☑️ Task", uniqueInboxTask, id), markup.Data("🕒 Timestamps", uniqueInboxTimestamps, id)),
+		markup.Row(markup.Data("🗑 Delete", uniqueInboxDelete, id), markup.Data("⬅️ Back", uniqueInboxBack)),
+	}
+	if len(sort) > 0 {
+		rows = append([]tb.Row{sort}, rows...)
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// inboxOptionsMarkup returns a button per folder or tag the note can be
+// sorted into.
+func inboxOptionsMarkup(id, unique, icon string, options []string) *tb.ReplyMarkup {
+	markup := &tb.ReplyMarkup{}
+	rows := make([]tb.Row, 0, len(options)+1)
+	for i, option := range options {
+		rows = append(rows, markup.Row(markup.Data(icon+" "+option, unique, id, strconv.Itoa(i))))
+	}
+
+	rows = append(rows, markup.Row(markup.Data("⬅️ Back", uniqueInboxOpen, id)))
+	markup.Inline(rows...)
+
+	return markup
+}
+
+func (br *bot) InboxHandler(ctx context.Context, b *tb.Bot) {
+	b.Handle(&tb.Btn{Unique: uniqueInboxOpen}, br.callback(ctx, uniqueInboxOpen, models.RoleReadOnly, func(ctx context.Context, c tb.Context) error {
+		note, err := br.ObsidianUsecase.GetInboxNote(ctx, c.Data())
+		if err != nil {
+			return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+				return "", err
+			})
+		}
+
+		if err := c.Respond(); err != nil {
+			return fmt.Errorf("respond to callback: %w", err)
+		}
+
+		text := fmt.Sprintf("**%s**\n`%s`\n\n%s", note.Title, note.Path, note.Preview)
+
+		return edit(b, c.Message(), text, br.inboxNoteMarkup(note.ID))
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxBack}, br.callback(ctx, uniqueInboxBack, models.RoleReadOnly, func(ctx context.Context, c tb.Context) error {
+		return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+			return "", nil
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxPage}, br.callback(ctx, uniqueInboxPage, models.RoleReadOnly, func(ctx context.Context, c tb.Context) error {
+		page, err := strconv.Atoi(c.Data())
+		if err != nil {
+			return fmt.Errorf("parse page [data = %q]: %w", c.Data(), err)
+		}
+
+		markup, err := br.inboxMarkup(ctx, page)
+		if err != nil {
+			return fmt.Errorf("get inbox markup: %w", err)
+		}
+
+		return br.showInboxOptions(b, c, markup)
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxMoveMenu}, br.callback(ctx, uniqueInboxMoveMenu, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		folders, _ := br.ObsidianUsecase.TriageOptions()
+		return br.showInboxOptions(b, c, inboxOptionsMarkup(c.Data(), uniqueInboxMove, "📁", folders))
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxRetagMenu}, br.callback(ctx, uniqueInboxRetagMenu, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		_, tags := br.ObsidianUsecase.TriageOptions()
+		return br.showInboxOptions(b, c, inboxOptionsMarkup(c.Data(), uniqueInboxRetag, "🏷", tags))
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxMove}, br.callback(ctx, uniqueInboxMove, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+			id, option, err := parseInboxOption(c.Args())
+			if err != nil {
+				return "", err
+			}
+
+			return br.ObsidianUsecase.MoveInboxNote(ctx, id, option)
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxRetag}, br.callback(ctx, uniqueInboxRetag, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+			id, option, err := parseInboxOption(c.Args())
+			if err != nil {
+				return "", err
+			}
+
+			return br.ObsidianUsecase.RetagInboxNote(ctx, id, option)
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxTask}, br.callback(ctx, uniqueInboxTask, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+			return br.ObsidianUsecase.InboxNoteToTask(ctx, c.Data())
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxTimestamps}, br.callback(ctx, uniqueInboxTimestamps, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+			return br.ObsidianUsecase.InboxNoteToTimestamps(ctx, c.Data())
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueInboxDelete}, br.callback(ctx, uniqueInboxDelete, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateInbox(ctx, b, c, func(ctx context.Context) (string, error) {
+			return br.ObsidianUsecase.DeleteInboxNote(ctx, c.Data())
+		})
+	}))
+}
+
+func (br *bot) showInboxOptions(b *tb.Bot, c tb.Context, markup *tb.ReplyMarkup) error {
+	if err := c.Respond(); err != nil {
+		return fmt.Errorf("respond to callback: %w", err)
+	}
+
+	if _, err := b.EditReplyMarkup(c.Message(), markup); err != nil {
+		return fmt.Errorf("edit reply markup: %w", err)
+	}
+
+	return nil
+}
+
+// parseInboxOption parses the note id and the index of a folder or tag.
+func parseInboxOption(args []string) (string, int, error) {
+	if len(args) != 2 {
+		return "", 0, fmt.Errorf("unexpected callback data %q", args)
+	}
+
+	option, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("parse option [data = %q]: %w", args[1], err)
+	}
+
+	return args[0], option, nil
+}
+
+// updateInbox runs the action, shows its result in the callback answer
+// and redraws the inbox list in place.
+func (br *bot) updateInbox(ctx context.Context, b *tb.Bot, c tb.Context, action func(ctx context.Context) (string, error)) error {
+	result, err := action(ctx)
+	if err != nil {
+		log.Errorf("inbox callback get error from handler: %v", err)
+		result = err.Error()
+	}
+
+	if err := c.Respond(&tb.CallbackResponse{Text: callbackText(result)}); err != nil {
+		return fmt.Errorf("respond to callback: %w", err)
+	}
+
+	text, err := br.ObsidianUsecase.GetInboxItems(ctx, "")
+	if err != nil {
+		return fmt.Errorf("get inbox: %w", err)
+	}
+
+	markup, err := br.InboxMarkup(ctx)
+	if err != nil {
+		return fmt.Errorf("get inbox markup: %w", err)
+	}
+
+	return edit(b, c.Message(), text, markup)
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+)
+
+// stubUsecase implements the methods the tests call, the others panic.
+type stubUsecase struct {
+	ObsidianUsecase
+
+	inbox []models.InboxNote
+}
+
+func (s *stubUsecase) GetInboxNotes(context.Context) ([]models.InboxNote, error) {
+	return s.inbox, nil
+}
+
+func TestInboxMarkupPages(t *testing.T) {
+	usecase := &stubUsecase{}
+	for i := 0; i < 2*inboxPageSize+1; i++ {
+		usecase.inbox = append(usecase.inbox, models.InboxNote{ID: fmt.Sprint(i), Title: fmt.Sprint("Note ", i)})
+	}
+	br := NewBot(usecase, nil)
+
+	tests := []struct {
+		name  string
+		page  int
+		first string
+		notes int
+		nav   []string
+	}{
+		{name: "first", page: 0, first: "0", notes: inboxPageSize, nav: []string{"1"}},
+		{name: "middle", page: 1, first: fmt.Sprint(inboxPageSize), notes: inboxPageSize, nav: []string{"0", "2"}},
+		{name: "last", page: 2, first: fmt.Sprint(2 * inboxPageSize), notes: 1, nav: []string{"1"}},
+		{name: "gone", page: 5, first: fmt.Sprint(2 * inboxPageSize), notes: 1, nav: []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markup, err := br.inboxMarkup(context.Background(), tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rows := markup.InlineKeyboard
+			if len(rows) != tt.notes+1 {
+				t.Fatalf("got %d rows, want %d notes and the navigation", len(rows), tt.notes)
+			}
+
+			if got := rows[0][0].Data; got != tt.first {
+				t.Errorf("first note = %q, want %q", got, tt.first)
+			}
+
+			var nav []string
+			for _, btn := range rows[len(rows)-1] {
+				nav = append(nav, btn.Data)
+			}
+
+			if strings.Join(nav, ",") != strings.Join(tt.nav, ",") {
+				t.Errorf("navigation = %q, want %q", nav, tt.nav)
+			}
+		})
+	}
+}
+
+func TestInboxMarkupSinglePage(t *testing.T) {
+	br := NewBot(&stubUsecase{inbox: []models.InboxNote{{ID: "1", Title: "Idea"}}}, nil)
+
+	markup, err := br.InboxMarkup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(markup.InlineKeyboard) != 1 {
+		t.Errorf("got %d rows, want the note only", len(markup.InlineKeyboard))
+	}
+}
+
+func TestCallbackText(t *testing.T) {
+	if got := callbackText("Done."); got != "Done." {
+		t.Errorf("callbackText() = %q", got)
+	}
+
+	got := callbackText(strings.Repeat("ё", 300))
+	if n := utf8.RuneCountInString(got); n != maxCallbackText || !strings.HasSuffix(got, "…") {
+		t.Errorf("callbackText() has %d characters: %q", n, got)
+	}
+}
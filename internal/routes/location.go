@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	log "github.com/sirupsen/logrus"
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	uniqueLocationTimestamps = "location_timestamps"
+	uniqueLocationInbox      = "location_inbox"
+)
+
+// LocationHandler asks where to save locations and venues: to today's
+// timestamps note or to the latest inbox note. The prompt replies to the
+// location message, so the buttons read the location from it.
+func (br *bot) LocationHandler(ctx context.Context, b *tb.Bot) {
+	prompt := func(c tb.Context) error {
+		user := c.Sender()
+
+		if user == nil {
+			return fmt.Errorf("nil sender for updateID=%d", c.Update().ID)
+		}
+
+		log.Infof("receive tg location: updateID=%d userID=%d username=%s",
+			c.Update().ID, user.ID, user.Username)
+
+		u, ok := br.checkUser(user.ID, models.RoleEditor)
+		if !ok {
+			return send(b, user, br.notAllowedMessage(user.ID), nil)
+		}
+
+		ctx := models.ContextWithUser(ctx, u)
+
+		markup := &tb.ReplyMarkup{}
+		rows := []tb.Row{markup.Row(markup.Data("🕒 Timestamps", uniqueLocationTimestamps))}
+
+		note, err := br.ObsidianUsecase.LatestInboxNote(ctx)
+		if err != nil {
+			log.Errorf("location prompt get error from handler: %v", err)
+		} else if note != nil {
+			rows = append(rows, markup.Row(markup.Data("📥 "+note.Title, uniqueLocationInbox, note.ID)))
+		}
+		markup.Inline(rows...)
+
+		_, err = b.Reply(c.Message(), "Where to save the location?", markup)
+		if err != nil {
+			return fmt.Errorf("send message: %w", err)
+		}
+
+		return nil
+	}
+
+	b.Handle(tb.OnLocation, prompt)
+	b.Handle(tb.OnVenue, prompt)
+
+	b.Handle(&tb.Btn{Unique: uniqueLocationTimestamps}, br.callback(ctx, uniqueLocationTimestamps, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.saveLocation(ctx, b, c, func(ctx context.Context, location models.Location) (string, error) {
+			return br.ObsidianUsecase.AddLocation(ctx, location)
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueLocationInbox}, br.callback(ctx, uniqueLocationInbox, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.saveLocation(ctx, b, c, func(ctx context.Context, location models.Location) (string, error) {
+			return br.ObsidianUsecase.SetInboxNoteLocation(ctx, c.Data(), location)
+		})
+	}))
+}
+
+// saveLocation runs the action with the location the prompt replies to and
+// replaces the prompt with the result.
+func (br *bot) saveLocation(ctx context.Context, b *tb.Bot, c tb.Context, action func(ctx context.Context, location models.Location) (string, error)) error {
+	if err := c.Respond(); err != nil {
+		return fmt.Errorf("respond to callback: %w", err)
+	}
+
+	previousChangeID := br.ObsidianUsecase.LastChangeID(ctx)
+
+	var result string
+	var markup *tb.ReplyMarkup
+	location, err := locationOf(c.Message().ReplyTo)
+	if err == nil {
+		result, err = action(ctx, location)
+	}
+	if err != nil {
+		log.Errorf("location callback get error from handler: %v", err)
+		result = fmt.Errorf("**Error occurred in saving location.**\n\n%w", err).Error()
+	} else {
+		markup = br.undoMarkup(ctx, previousChangeID)
+	}
+
+	return edit(b, c.Message(), result, markup)
+}
+
+func locationOf(msg *tb.Message) (models.Location, error) {
+	switch {
+	case msg == nil:
+		return models.Location{}, fmt.Errorf("location message is not available anymore")
+	case msg.Venue != nil:
+		location := newLocation(msg.Venue.Location)
+		location.Venue, location.Address = msg.Venue.Title, msg.Venue.Address
+
+		return location, nil
+	case msg.Location != nil:
+		return newLocation(*msg.Location), nil
+	}
+
+	return models.Location{}, fmt.Errorf("message has no location")
+}
+
+// newLocation rounds coordinates to 6 digits, about 10 cm, hiding the noise
+// of their float32 representation.
+func newLocation(location tb.Location) models.Location {
+	round := func(value float32) float64 {
+		return math.Round(float64(value)*1e6) / 1e6
+	}
+
+	return models.Location{Lat: round(location.Lat), Lon: round(location.Lng)}
+}
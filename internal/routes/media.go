@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+// MediaHandler saves photos, documents and voice messages into the vault,
+// the caption tells where to embed photos and documents.
+func (br *bot) MediaHandler(ctx context.Context, b *tb.Bot) {
+	b.Handle(tb.OnPhoto, br.message(ctx, b, "photo", func(ctx context.Context, c tb.Context) (string, error) {
+		photo := c.Message().Photo
+
+		// photos have no name, the extension is taken from the file path
+		return br.saveFile(ctx, b, &photo.File, "", c.Message().Caption)
+	}))
+
+	b.Handle(tb.OnDocument, br.message(ctx, b, "document", func(ctx context.Context, c tb.Context) (string, error) {
+		document := c.Message().Document
+
+		return br.saveFile(ctx, b, &document.File, document.FileName, c.Message().Caption)
+	}))
+
+	b.Handle(tb.OnVoice, br.message(ctx, b, "voice", func(ctx context.Context, c tb.Context) (string, error) {
+		reader, err := b.File(&c.Message().Voice.File)
+		if err != nil {
+			return "", fmt.Errorf("download voice: %w", err)
+		}
+		defer reader.Close()
+
+		return br.ObsidianUsecase.SaveVoice(ctx, reader, c.Message().Caption)
+	}))
+}
+
+func (br *bot) saveFile(ctx context.Context, b *tb.Bot, file *tb.File, name, caption string) (string, error) {
+	reader, err := b.File(file)
+	if err != nil {
+		return "", fmt.Errorf("download file: %w", err)
+	}
+	defer reader.Close()
+
+	if name == "" {
+		name = path.Base(file.FilePath)
+	}
+
+	return br.ObsidianUsecase.SaveAttachment(ctx, name, reader, caption)
+}
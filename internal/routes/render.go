@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+// maxMessageLength keeps rendered pages below the Telegram limit of 4096
+// characters, leaving room for the page counter.
+const maxMessageLength = 4000
+
+var (
+	headingRe   = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	checkboxRe  = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	bulletRe    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	codeRe      = regexp.MustCompile("`([^`]+)`")
+	linkRe      = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	wikilinkRe  = regexp.MustCompile(`!?\[\[([^\]|]+)(?:\|([^\]]+))?\]\]`)
+	boldRe      = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	strikeRe    = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	highlightRe = regexp.MustCompile(`==(\S(?:.*?\S)?)==`)
+	// the markers stand between spaces or punctuation, so underscores of
+	// paths and names like "_inbox_/a.md" or "snake_case" are kept
+	italicRe = regexp.MustCompile(`(^|[\s(])[*_]([^\s*_](?:[^*_]*?[^\s*_])?)[*_]([\s.,;:!?)&]|$)`)
+)
+
+// renderLine converts a line of Obsidian Markdown from usecase replies to
+// Telegram HTML. Lines are converted on their own, so tags never span
+// lines and replies can be split into pages by lines.
+func renderLine(line string) string {
+	if match := headingRe.FindStringSubmatch(line); match != nil {
+		return "<b>" + renderInline(match[1]) + "</b>"
+	}
+
+	if match := checkboxRe.FindStringSubmatch(line); match != nil {
+		box := "☐"
+		if match[2] != " " {
+			box = "☑"
+		}
+
+		return match[1] + box + " " + renderInline(match[3])
+	}
+
+	if match := bulletRe.FindStringSubmatch(line); match != nil {
+		return match[1] + "• " + renderInline(match[2])
+	}
+
+	return renderInline(line)
+}
+
+func renderInline(text string) string {
+	// Code spans and links are replaced by placeholders first, so their
+	// content is not formatted.
+	var spans []string
+	hold := func(s string) string {
+		spans = append(spans, s)
+		return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+	}
+
+	text = codeRe.ReplaceAllStringFunc(text, func(s string) string {
+		return hold("<code>" + html.EscapeString(codeRe.FindStringSubmatch(s)[1]) + "</code>")
+	})
+	text = linkRe.ReplaceAllStringFunc(text, func(s string) string {
+		match := linkRe.FindStringSubmatch(s)
+		return hold(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(match[2]), html.EscapeString(match[1])))
+	})
+	text = wikilinkRe.ReplaceAllStringFunc(text, func(s string) string {
+		match := wikilinkRe.FindStringSubmatch(s)
+		name := match[1]
+		if match[2] != "" {
+			name = match[2]
+		}
+
+		return hold("<u>" + html.EscapeString(name) + "</u>")
+	})
+
+	text = html.EscapeString(text)
+	text = boldRe.ReplaceAllString(text, "<b>$1$2</b>")
+	text = strikeRe.ReplaceAllString(text, "<s>$1</s>")
+	text = highlightRe.ReplaceAllString(text, "<u>$1</u>")
+	// adjacent matches share the space between them, the next pass
+	// replaces the ones skipped
+	for {
+		italic := italicRe.ReplaceAllString(text, "$1<i>$2</i>$3")
+		if italic == text {
+			break
+		}
+		text = italic
+	}
+
+	for i, span := range spans {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+
+	return text
+}
+
+// paginate renders the text and splits it into pages fitting into one
+// Telegram message. Pages are split by lines, too long lines are cut.
+func paginate(text string) []string {
+	var pages []string
+	var page strings.Builder
+
+	for _, line := range splitLongLines(strings.Split(text, "\n")) {
+		rendered := renderLine(line)
+		if page.Len() > 0 && utf8.RuneCountInString(page.String())+utf8.RuneCountInString(rendered)+1 > maxMessageLength {
+			pages = append(pages, page.String())
+			page.Reset()
+		}
+
+		if page.Len() > 0 {
+			page.WriteString("\n")
+		}
+		page.WriteString(rendered)
+	}
+
+	pages = append(pages, page.String())
+
+	if len(pages) > 1 {
+		for i := range pages {
+			pages[i] += fmt.Sprintf("\n\n<i>%d/%d</i>", i+1, len(pages))
+		}
+	}
+
+	return pages
+}
+
+// splitLongLines cuts lines which could not fit into a page after
+// rendering. Escaping and tags make a line longer, so the rendered length
+// is measured.
+func splitLongLines(lines []string) []string {
+	var result []string
+	for _, line := range lines {
+		result = append(result, splitLine(line)...)
+	}
+
+	return result
+}
+
+// splitLine cuts the line in halves until every part fits into a page.
+func splitLine(line string) []string {
+	if utf8.RuneCountInString(renderLine(line)) <= maxMessageLength {
+		return []string{line}
+	}
+
+	runes := []rune(line)
+	half := len(runes) / 2
+
+	return append(splitLine(string(runes[:half])), splitLine(string(runes[half:]))...)
+}
+
+// send renders the reply and sends it in as many messages as needed, the
+// markup is attached to the last one.
+func send(b *tb.Bot, to tb.Recipient, text string, markup *tb.ReplyMarkup) error {
+	pages := paginate(text)
+	for i, page := range pages {
+		opts := []interface{}{tb.ModeHTML, tb.NoPreview}
+		if i == len(pages)-1 && markup != nil {
+			opts = append(opts, markup)
+		}
+
+		if _, err := b.Send(to, page, opts...); err != nil {
+			return fmt.Errorf("send page %d/%d: %w", i+1, len(pages), err)
+		}
+	}
+
+	return nil
+}
+
+// edit renders the reply into an existing message. A message holds one
+// page only, so longer replies are cut.
+func edit(b *tb.Bot, msg tb.Editable, text string, markup *tb.ReplyMarkup) error {
+	pages := paginate(text)
+
+	page := pages[0]
+	if len(pages) > 1 {
+		page = strings.TrimSuffix(page, fmt.Sprintf("\n\n<i>1/%d</i>", len(pages))) + "\n…"
+	}
+
+	_, err := b.Edit(msg, page, tb.ModeHTML, tb.NoPreview, markup)
+	if err != nil && err != tb.ErrSameMessageContent {
+		return fmt.Errorf("edit message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRenderLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "heading", line: "## Inbox", want: "<b>Inbox</b>"},
+		{name: "checkbox", line: "  - [x] milk", want: "  ☑ milk"},
+		{name: "bullet", line: "- eggs", want: "• eggs"},
+		{name: "escape", line: "a < b & c", want: "a &lt; b &amp; c"},
+		{name: "bold", line: "**Please** sort", want: "<b>Please</b> sort"},
+		{name: "italic", line: "_sort inbox_ now", want: "<i>sort inbox</i> now"},
+		{name: "adjacent italic", line: "*a* _b_", want: "<i>a</i> <i>b</i>"},
+		{name: "italic in parentheses", line: "(see _this_).", want: "(see <i>this</i>)."},
+		{name: "path", line: "/obsidian/_inbox_/a.md", want: "/obsidian/_inbox_/a.md"},
+		{name: "path at start", line: "_inbox_/a.md", want: "_inbox_/a.md"},
+		{name: "snake case", line: "snake_case_name", want: "snake_case_name"},
+		{name: "code", line: "run `go *test*`", want: "run <code>go *test*</code>"},
+		{name: "link", line: "[Go](https://go.dev)", want: `<a href="https://go.dev">Go</a>`},
+		{name: "wikilink", line: "see [[Note|alias]]", want: "see <u>alias</u>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderLine(tt.line); got != tt.want {
+				t.Errorf("renderLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	pages := paginate("# Title\n- item")
+	if len(pages) != 1 || pages[0] != "<b>Title</b>\n• item" {
+		t.Fatalf("paginate() = %q", pages)
+	}
+
+	// escaping makes the line 4 times longer
+	pages = paginate(strings.Repeat("<", 3*maxMessageLength))
+	if len(pages) < 3 {
+		t.Fatalf("paginate() = %d pages, want at least 3", len(pages))
+	}
+
+	var escaped int
+	for i, page := range pages {
+		if n := utf8.RuneCountInString(page); n > 4096 {
+			t.Errorf("page %d has %d characters", i+1, n)
+		}
+		escaped += strings.Count(page, "&lt;")
+	}
+
+	if escaped != 3*maxMessageLength {
+		t.Errorf("pages hold %d characters, want %d", escaped, 3*maxMessageLength)
+	}
+
+	if !strings.HasSuffix(pages[0], fmt.Sprintf("\n\n<i>1/%d</i>", len(pages))) {
+		t.Errorf("first page has no counter: %q", pages[0][len(pages[0])-20:])
+	}
+}
@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	log "github.com/sirupsen/logrus"
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	uniqueShoppingToggle        = "shopping_toggle"
+	uniqueShoppingRemoveChecked = "shopping_remove_checked"
+)
+
+// ShoppingListMarkup returns an inline keyboard with a button per item of
+// the shopping list, tapping a button checks or unchecks the item.
+func (br *bot) ShoppingListMarkup(ctx context.Context) (*tb.ReplyMarkup, error) {
+	items, err := br.ObsidianUsecase.GetShoppingItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get shopping items: %w", err)
+	}
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	markup := &tb.ReplyMarkup{}
+	rows := make([]tb.Row, 0, len(items)+1)
+	for _, item := range items {
+		text := "⬜ " + item.Text
+		if item.Checked {
+			text = "✅ " + item.Text
+		}
+
+		if item.Depth > 0 {
+			text = strings.Repeat("  ", item.Depth-1) + "↳ " + text
+		}
+
+		rows = append(rows, markup.Row(markup.Data(text, uniqueShoppingToggle, item.ID)))
+	}
+
+	rows = append(rows, markup.Row(markup.Data("🗑 Remove checked", uniqueShoppingRemoveChecked)))
+	markup.Inline(rows...)
+
+	return markup, nil
+}
+
+func (br *bot) ShoppingListHandler(ctx context.Context, b *tb.Bot) {
+	b.Handle(&tb.Btn{Unique: uniqueShoppingToggle}, br.callback(ctx, uniqueShoppingToggle, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateShoppingList(ctx, b, c, func(ctx context.Context) (string, error) {
+			return br.ObsidianUsecase.ToggleShoppingItem(ctx, c.Data())
+		})
+	}))
+
+	b.Handle(&tb.Btn{Unique: uniqueShoppingRemoveChecked}, br.callback(ctx, uniqueShoppingRemoveChecked, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		return br.updateShoppingList(ctx, b, c, func(ctx context.Context) (string, error) {
+			return br.ObsidianUsecase.RemoveCheckedShoppingItems(ctx, "")
+		})
+	}))
+}
+
+// updateShoppingList runs the action, shows its result in the callback
+// answer and redraws the shopping list message in place.
+func (br *bot) updateShoppingList(ctx context.Context, b *tb.Bot, c tb.Context, action func(ctx context.Context) (string, error)) error {
+	result, err := action(ctx)
+	if err != nil {
+		log.Errorf("shopping list callback get error from handler: %v", err)
+		result = err.Error()
+	}
+
+	if err := c.Respond(&tb.CallbackResponse{Text: callbackText(result)}); err != nil {
+		return fmt.Errorf("respond to callback: %w", err)
+	}
+
+	text, err := br.ObsidianUsecase.GetShoppingList(ctx, "")
+	if err != nil {
+		return fmt.Errorf("get shopping list: %w", err)
+	}
+
+	markup, err := br.ShoppingListMarkup(ctx)
+	if err != nil {
+		return fmt.Errorf("get shopping list markup: %w", err)
+	}
+
+	return edit(b, c.Message(), text, markup)
+}
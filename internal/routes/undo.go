@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	log "github.com/sirupsen/logrus"
+	tb "gopkg.in/telebot.v3"
+)
+
+const uniqueUndo = "undo"
+
+// undoMarkup returns an inline keyboard with the "Undo" button if the
+// handler made a change after the change with previousID.
+func (br *bot) undoMarkup(ctx context.Context, previousID int64) *tb.ReplyMarkup {
+	changeID := br.ObsidianUsecase.LastChangeID(ctx)
+	if changeID == 0 || changeID == previousID {
+		return nil
+	}
+
+	markup := &tb.ReplyMarkup{}
+	markup.Inline(markup.Row(markup.Data("↩️ Undo", uniqueUndo, strconv.FormatInt(changeID, 10))))
+
+	return markup
+}
+
+func (br *bot) UndoHandler(ctx context.Context, b *tb.Bot) {
+	b.Handle(&tb.Btn{Unique: uniqueUndo}, br.callback(ctx, uniqueUndo, models.RoleEditor, func(ctx context.Context, c tb.Context) error {
+		changeID, err := strconv.ParseInt(c.Data(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse change id [data = %q]: %w", c.Data(), err)
+		}
+
+		userFriendlyMessage, err := br.ObsidianUsecase.UndoChange(ctx, changeID)
+		if err != nil {
+			log.Errorf("undo get error from handler: %v", err)
+			userFriendlyMessage = fmt.Errorf("**Error occurred in undo.**\n\n%w", err).Error()
+		}
+
+		if err := c.Respond(); err != nil {
+			return fmt.Errorf("respond to callback: %w", err)
+		}
+
+		// the button is single use
+		if _, err := b.EditReplyMarkup(c.Message(), nil); err != nil {
+			log.Warnf("remove undo button: %v", err)
+		}
+
+		err = send(b, c.Sender(), userFriendlyMessage, nil)
+		if err != nil {
+			return fmt.Errorf("send message: %w", err)
+		}
+
+		return nil
+	}))
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxAttachmentSize is the largest file the Bot API lets bots download.
+const maxAttachmentSize = 20 << 20
+
+var forbiddenFileNameChars = strings.NewReplacer("#", "", "^", "", "[", "", "]", "")
+
+// SaveAttachment saves the file into the attachments folder and embeds it
+// depending on the caption: "#action text" appends it to today's timestamps
+// note, "#inbox title" or a caption without a tag creates an inbox note.
+func (us *obsidian) SaveAttachment(ctx context.Context, name string, file io.Reader, caption string) (string, error) {
+	toTimestamps, text, err := parseAttachmentCaption(caption)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := readAttachment(file)
+	if err != nil {
+		return "", err
+	}
+
+	fp, err := us.writeAttachment(ctx, name, data)
+	if err != nil {
+		return "", fmt.Errorf("write attachment: %w", err)
+	}
+
+	embed := fmt.Sprintf("![[%s]]", filepath.ToSlash(fp))
+
+	if toTimestamps {
+		timestamps, err := us.appendToTimestamps(ctx, strings.TrimSpace(text+"\n"+embed))
+		if err != nil {
+			us.removeAttachment(ctx, fp)
+			return "", err
+		}
+
+		return fmt.Sprintf("Successfully save %q and add it to %s.", fp, timestamps), nil
+	}
+
+	if text == "" {
+		text = strings.TrimSuffix(filepath.Base(fp), filepath.Ext(fp))
+	}
+
+	return us.createAttachmentNote(ctx, fp, text, embed)
+}
+
+// createAttachmentNote creates the inbox note embedding the attachment, the
+// attachment is removed when the note can't be created.
+func (us *obsidian) createAttachmentNote(ctx context.Context, fp, title, body string) (string, error) {
+	title, err := us.writeInboxNote(ctx, title, body, nil)
+	if err != nil {
+		// nothing embeds the attachment, so it is not kept
+		us.removeAttachment(ctx, fp)
+
+		if errors.Is(err, errNoteExists) {
+			return "Note with such name already exist.", nil
+		}
+
+		return "", err
+	}
+
+	return fmt.Sprintf("Successfully create note %q with inbox tag.", title), nil
+}
+
+func (us *obsidian) removeAttachment(ctx context.Context, fp string) {
+	if err := us.repo(ctx).RemoveFile(fp); err != nil {
+		log.Errorf("remove unused attachment: %v", err)
+	}
+}
+
+// parseAttachmentCaption returns whether the attachment goes to the
+// timestamps note and the caption text without the tag.
+func parseAttachmentCaption(caption string) (bool, string, error) {
+	caption = strings.TrimSpace(caption)
+
+	tag, arg, text, err := extractTagAndText(caption)
+	if err != nil {
+		return false, caption, nil
+	}
+
+	switch Tag(tag) {
+	case TagAction:
+		return true, textOrArg(text, arg), nil
+	case TagInbox:
+		return false, textOrArg(text, arg), nil
+	}
+
+	if !isKnownTag(tag) {
+		return false, caption, nil
+	}
+
+	return false, "", fmt.Errorf("files can't be added with #%s, use #%s or #%s", tag, TagInbox, TagAction)
+}
+
+func readAttachment(file io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxAttachmentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	if len(data) > maxAttachmentSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxAttachmentSize>>20)
+	}
+
+	return data, nil
+}
+
+// writeAttachment writes the file under a free name in the attachments
+// folder. Attachments are not recorded for undo, so the history does not
+// hold their content, undo removes only the note embedding them.
+func (us *obsidian) writeAttachment(ctx context.Context, name string, data []byte) (string, error) {
+	name = sanitizeTitle(forbiddenFileNameChars.Replace(filepath.Base(name)))
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "" {
+		base = fmt.Sprintf("file %s", us.Clock.Now().Format("2006-01-02 150405"))
+	}
+
+	err := us.repo(ctx).CreateDir(us.Vault.AttachmentsDir)
+	if err != nil {
+		return "", fmt.Errorf("create attachments dir: %w", err)
+	}
+
+	// a free name is taken by creating the file, so equal names sent at
+	// the same time don't overwrite each other
+	fp := filepath.Join(us.Vault.AttachmentsDir, base+ext)
+	for i := 1; ; i++ {
+		err = us.repo(ctx).WriteNewFile(fp, string(data))
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+
+		fp = filepath.Join(us.Vault.AttachmentsDir, fmt.Sprintf("%s %d%s", base, i, ext))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return fp, nil
+}
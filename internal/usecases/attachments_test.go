@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAttachment(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+	ctx := testContext()
+
+	msg, err := us.SaveAttachment(ctx, "photo.jpg", strings.NewReader("jpeg"), "Sunset")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != `Successfully create note "Sunset" with inbox tag.` {
+		t.Errorf("SaveAttachment() = %q", msg)
+	}
+
+	if got := readTestFile(t, dir, "Attachments/photo.jpg"); got != "jpeg" {
+		t.Errorf("attachment = %q", got)
+	}
+
+	if got := readTestFile(t, dir, "Sunset.md"); !strings.Contains(got, "![[Attachments/photo.jpg]]") {
+		t.Errorf("note = %q, want the attachment embedded", got)
+	}
+}
+
+func TestSaveAttachmentToExistingNote(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Sunset.md": "# Sunset\n",
+	})
+
+	msg, err := us.SaveAttachment(testContext(), "photo.jpg", strings.NewReader("jpeg"), "Sunset")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != "Note with such name already exist." {
+		t.Errorf("SaveAttachment() = %q", msg)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "Attachments/photo.jpg")); !os.IsNotExist(err) {
+		t.Errorf("attachment is left without a note: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/r-mol/ObsidianBot/pkg/markdown"
+)
+
+// category is a store section of the shopping list with keywords of the
+// items which belong to it.
+type category struct {
+	Title    string
+	Keywords []string
+}
+
+// readCategories reads the keyword to category mapping note. Headings of
+// the note are categories and list items under them are keywords:
+//
+//	## Dairy
+//	- milk
+//	- cheese
+//
+// A missing note means there are no categories.
+func (us *obsidian) readCategories(ctx context.Context) ([]category, error) {
+	if us.Vault.ShoppingCategories == "" {
+		return nil, nil
+	}
+
+	exist, err := us.repo(ctx).FileExist(us.Vault.ShoppingCategories)
+	if err != nil {
+		return nil, fmt.Errorf("check file exist: %w", err)
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	data, err := us.repo(ctx).ReadFromFile(us.Vault.ShoppingCategories)
+	if err != nil {
+		return nil, fmt.Errorf("read from file: %w", err)
+	}
+
+	doc, err := markdown.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse categories: %w", err)
+	}
+
+	var categories []category
+	for _, section := range doc.Sections {
+		if section.Title == "" {
+			continue
+		}
+
+		c := category{Title: section.Title}
+		doc := &markdown.Document{Sections: []*markdown.Section{section}}
+		doc.Walk(func(_ *markdown.Section, item *markdown.Item, _ int) {
+			if keyword := normalizeWords(item.Text); keyword != "" {
+				c.Keywords = append(c.Keywords, keyword)
+			}
+		})
+
+		categories = append(categories, c)
+	}
+
+	return categories, nil
+}
+
+// findCategory returns the title of the category with the given name.
+func findCategory(categories []category, name string) (string, bool) {
+	for _, c := range categories {
+		if strings.EqualFold(c.Title, name) {
+			return c.Title, true
+		}
+	}
+
+	return "", false
+}
+
+// matchCategory returns the category which has a keyword found in the
+// item as a whole word or phrase, the longest keyword wins.
+func matchCategory(categories []category, item string) (string, bool) {
+	text := " " + normalizeWords(item) + " "
+
+	var title string
+	var longest int
+	for _, c := range categories {
+		for _, keyword := range c.Keywords {
+			if len(keyword) > longest && strings.Contains(text, " "+keyword+" ") {
+				title, longest = c.Title, len(keyword)
+			}
+		}
+	}
+
+	return title, longest > 0
+}
+
+// normalizeWords lowercases the text and keeps only words in singular
+// form separated by a space.
+func normalizeWords(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = singular(word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// singular strips simple English plural endings: "berries" -> "berry",
+// "apples" -> "apple". Words like "glass" are left untouched.
+func singular(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+
+	return word
+}
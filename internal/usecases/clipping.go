@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+	"github.com/r-mol/ObsidianBot/pkg/webpage"
+	log "github.com/sirupsen/logrus"
+)
+
+// linkLabelEscaper escapes brackets of page titles, which would end the
+// label of the markdown link early.
+var linkLabelEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+const (
+	tagClipping  = "clipping"
+	tagForwarded = "forwarded"
+	// maxTitleLength is the longest note title taken from a message.
+	maxTitleLength = 60
+)
+
+// parseURL reports whether the message is a single http(s) link.
+func parseURL(msg string) (string, bool) {
+	msg = strings.TrimSpace(msg)
+	if msg == "" || strings.ContainsAny(msg, " \t\n") {
+		return "", false
+	}
+
+	u, err := url.Parse(msg)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+
+	return msg, true
+}
+
+// ClipURL creates an inbox note for the link titled and described by the
+// page. The note is created with the link only when the page can't be read.
+func (us *obsidian) ClipURL(ctx context.Context, link string) (string, error) {
+	page, err := us.Fetcher.Fetch(ctx, link)
+	if err != nil {
+		log.Warnf("fetch clipping: %v", err)
+		page = &webpage.Page{}
+	}
+
+	title := page.Title
+	if title == "" {
+		u, _ := url.Parse(link)
+		title = strings.ReplaceAll(strings.Trim(u.Hostname()+u.Path, "/"), "/", " ")
+	}
+
+	body := fmt.Sprintf("[%s](%s)", linkLabelEscaper.Replace(title), link)
+	if page.Description != "" {
+		body += "\n\n> " + page.Description
+	}
+
+	return us.createInboxNote(ctx, titleFromText(title), body, func(note *frontmatter.Note) error {
+		if err := note.Set("source", link); err != nil {
+			return err
+		}
+
+		if page.Description != "" {
+			if err := note.Set("description", page.Description); err != nil {
+				return err
+			}
+		}
+
+		return addTag(note, tagClipping)
+	})
+}
+
+// SaveForwarded creates an inbox note from the forwarded message with its
+// source in the properties.
+func (us *obsidian) SaveForwarded(ctx context.Context, forward models.Forward, text string) (string, error) {
+	source := forward.Chat
+	if source == "" {
+		source = forward.Author
+	}
+
+	title := titleFromText(text)
+	if title == "" {
+		title = fmt.Sprintf("Forward from %s", source)
+	}
+
+	return us.createInboxNote(ctx, title, text, func(note *frontmatter.Note) error {
+		properties := []struct {
+			key   string
+			value string
+		}{
+			{"forwarded_from", forward.Chat},
+			{"author", forward.Author},
+			{"date", forward.Date.In(us.Clock.Location()).Format("2006-01-02 15:04")},
+			{"link", forward.Link},
+		}
+
+		for _, property := range properties {
+			if property.value == "" || (property.key == "date" && forward.Date.IsZero()) {
+				continue
+			}
+
+			if err := note.Set(property.key, property.value); err != nil {
+				return err
+			}
+		}
+
+		return addTag(note, tagForwarded)
+	})
+}
+
+// addTag adds the tag to the tags property of the note.
+func addTag(note *frontmatter.Note, tag string) error {
+	tags := note.Tags()
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return nil
+		}
+	}
+
+	return note.Set("tags", append(tags, tag))
+}
+
+// titleFromText returns the first line of the text cut to a word boundary.
+func titleFromText(text string) string {
+	var title string
+	for _, line := range strings.Split(text, "\n") {
+		if title = strings.TrimSpace(strings.TrimLeft(line, "#>*-_ \t")); title != "" {
+			break
+		}
+	}
+
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+
+	cut := string([]rune(title)[:maxTitleLength])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+
+	return cut
+}
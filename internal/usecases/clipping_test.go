@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/r-mol/ObsidianBot/pkg/webpage"
+)
+
+type stubFetcher struct {
+	page *webpage.Page
+}
+
+func (f stubFetcher) Fetch(context.Context, string) (*webpage.Page, error) {
+	return f.page, nil
+}
+
+func TestClipURL(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+	us.Fetcher = stubFetcher{page: &webpage.Page{Title: "[RFC] Go [generics]", Description: "about generics"}}
+
+	if _, err := us.ParseMessage(testContext(), "https://example.com/rfc"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readTestFile(t, dir, "[RFC] Go [Generics].md")
+	if !strings.Contains(got, `[\[RFC\] Go \[generics\]](https://example.com/rfc)`) {
+		t.Errorf("note = %q, want the link with escaped label", got)
+	}
+
+	if !strings.Contains(got, "source: https://example.com/rfc") {
+		t.Errorf("note = %q, want the source property", got)
+	}
+}
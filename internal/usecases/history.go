@@ -0,0 +1,408 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"golang.org/x/exp/slices"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const historySize = 20
+
+var errFileChanged = errors.New("file has changed since the last change")
+
+// change is a snapshot of the files taken before a usecase modified them.
+type change struct {
+	ID    int64
+	Files []fileChange
+}
+
+type fileChange struct {
+	Path    string
+	Existed bool
+	Removed bool
+	Before  string
+	After   string
+}
+
+type batchKey struct{}
+
+// batch collects file changes of one usecase, so they are undone together.
+type batch struct {
+	files []fileChange
+}
+
+// history keeps the last changes of every user, oldest are dropped first.
+type history struct {
+	mu      sync.Mutex
+	lastID  int64
+	changes map[int64][]change
+}
+
+func (h *history) push(userID int64, ch change) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.changes == nil {
+		h.changes = make(map[int64][]change)
+	}
+
+	h.lastID++
+	ch.ID = h.lastID
+
+	changes := append(h.changes[userID], ch)
+	if len(changes) > historySize {
+		changes = changes[len(changes)-historySize:]
+	}
+	h.changes[userID] = changes
+
+	return ch.ID
+}
+
+func (h *history) last(userID int64) (change, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changes := h.changes[userID]
+	if len(changes) == 0 {
+		return change{}, false
+	}
+
+	return changes[len(changes)-1], true
+}
+
+// pop removes the last change if it has the given id, zero id matches any.
+func (h *history) pop(userID, id int64) (change, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changes := h.changes[userID]
+	if len(changes) == 0 {
+		return change{}, false
+	}
+
+	ch := changes[len(changes)-1]
+	if id != 0 && ch.ID != id {
+		return change{}, false
+	}
+
+	h.changes[userID] = changes[:len(changes)-1]
+
+	return ch, true
+}
+
+// putBack returns the popped change to the history, keeping its id.
+func (h *history) putBack(userID int64, ch change) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changes := append(h.changes[userID], ch)
+	if len(changes) > historySize {
+		changes = changes[len(changes)-historySize:]
+	}
+	h.changes[userID] = changes
+}
+
+// updateFile applies update to the file and records the change for undo.
+func (us *obsidian) updateFile(ctx context.Context, fp string, update func(data string) (string, error)) error {
+	var before, after string
+	err := us.repo(ctx).UpdateFile(fp, func(data string) (string, error) {
+		var err error
+		before = data
+		after, err = update(data)
+
+		return after, err
+	})
+	if err != nil {
+		return err
+	}
+
+	us.record(ctx, fileChange{Path: fp, Existed: true, Before: before, After: after})
+
+	return nil
+}
+
+// upsertFile applies update to the file, a missing file is updated from
+// empty content and created, and records the change for undo.
+func (us *obsidian) upsertFile(ctx context.Context, fp string, update func(data string) (string, error)) error {
+	var existed bool
+	var before, after string
+	err := us.repo(ctx).UpsertFile(fp, func(data string, exist bool) (string, error) {
+		var err error
+		existed, before = exist, data
+		after, err = update(data)
+
+		return after, err
+	})
+	if err != nil {
+		return err
+	}
+
+	us.record(ctx, fileChange{Path: fp, Existed: existed, Before: before, After: after})
+
+	return nil
+}
+
+// createFile writes a new file and records its creation for undo, it
+// fails if the file already exists.
+func (us *obsidian) createFile(ctx context.Context, fp string, data string) error {
+	err := us.repo(ctx).WriteNewFile(fp, data)
+	if err != nil {
+		return err
+	}
+
+	us.record(ctx, fileChange{Path: fp, After: data})
+
+	return nil
+}
+
+// removeFile removes the file and records its content for undo.
+func (us *obsidian) removeFile(ctx context.Context, fp string) error {
+	data, err := us.repo(ctx).ReadFromFile(fp)
+	if err != nil {
+		return err
+	}
+
+	if err := us.repo(ctx).RemoveFile(fp); err != nil {
+		return err
+	}
+
+	us.record(ctx, fileChange{Path: fp, Existed: true, Removed: true, Before: data})
+
+	return nil
+}
+
+// moveFile moves the file to a new path, the target must not exist.
+func (us *obsidian) moveFile(ctx context.Context, from, to string) error {
+	exist, err := us.repo(ctx).FileExist(to)
+	if err != nil {
+		return fmt.Errorf("check file exist: %w", err)
+	}
+
+	if exist {
+		return fmt.Errorf("file already exists [filepath = %q]", to)
+	}
+
+	data, err := us.repo(ctx).ReadFromFile(from)
+	if err != nil {
+		return err
+	}
+
+	if err := us.repo(ctx).CreateDir(filepath.Dir(to)); err != nil {
+		return err
+	}
+
+	if err := us.createFile(ctx, to, data); err != nil {
+		return err
+	}
+
+	return us.removeFile(ctx, from)
+}
+
+// batch runs fn recording all its file changes as one change, even when
+// fn fails halfway, so a partial result can be undone as well.
+func (us *obsidian) batch(ctx context.Context, fn func(ctx context.Context) error) error {
+	b := &batch{}
+	err := fn(context.WithValue(ctx, batchKey{}, b))
+
+	if len(b.files) > 0 {
+		if user, ok := models.UserFromContext(ctx); ok {
+			us.history.push(user.ID, change{Files: b.files})
+		}
+	}
+
+	return err
+}
+
+func (us *obsidian) record(ctx context.Context, fc fileChange) {
+	if b, ok := ctx.Value(batchKey{}).(*batch); ok {
+		b.files = append(b.files, fc)
+		return
+	}
+
+	if user, ok := models.UserFromContext(ctx); ok {
+		us.history.push(user.ID, change{Files: []fileChange{fc}})
+	}
+}
+
+// LastChangeID returns the id of the last change made by the acting user.
+func (us *obsidian) LastChangeID(ctx context.Context) int64 {
+	user, ok := models.UserFromContext(ctx)
+	if !ok {
+		return 0
+	}
+
+	ch, ok := us.history.last(user.ID)
+	if !ok {
+		return 0
+	}
+
+	return ch.ID
+}
+
+func (us *obsidian) Undo(ctx context.Context, msg string) (string, error) {
+	return us.UndoChange(ctx, 0)
+}
+
+// UndoChange restores the files modified by the last change of the acting
+// user, if it is the change with the given id and none of the files was
+// edited after it. Zero id undoes the last change whatever it is. A change
+// which can't be undone is kept, files restored before a file edited in the
+// meantime are brought back to their state after the change.
+func (us *obsidian) UndoChange(ctx context.Context, id int64) (string, error) {
+	user, ok := models.UserFromContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no user in context")
+	}
+
+	ch, ok := us.history.last(user.ID)
+	if !ok {
+		return "Nothing to undo.", nil
+	}
+
+	if id != 0 && ch.ID != id {
+		return "Only the last change can be undone.", nil
+	}
+
+	paths := make([]string, 0, len(ch.Files))
+	for i := len(ch.Files) - 1; i >= 0; i-- {
+		// only the last change of a file tells its current state
+		fc := ch.Files[i]
+		path := fmt.Sprintf("%q", fc.Path)
+		if slices.Contains(paths, path) {
+			continue
+		}
+		paths = append(paths, path)
+
+		err := us.checkFile(ctx, fc)
+		if errors.Is(err, errFileChanged) {
+			return fmt.Sprintf("Can't undo, %q has changed since.", fc.Path), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("check file [filepath = %q]: %w", fc.Path, err)
+		}
+	}
+
+	if _, ok := us.history.pop(user.ID, ch.ID); !ok {
+		return "Only the last change can be undone.", nil
+	}
+
+	for i := len(ch.Files) - 1; i >= 0; i-- {
+		fc := ch.Files[i]
+
+		err := us.restoreFile(ctx, fc)
+		if err != nil {
+			us.rollback(ctx, ch.Files[i+1:])
+			us.history.putBack(user.ID, ch)
+		}
+		if errors.Is(err, errFileChanged) {
+			// the file was edited right after the check
+			return fmt.Sprintf("Can't undo, %q has changed since.", fc.Path), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("restore file [filepath = %q]: %w", fc.Path, err)
+		}
+	}
+
+	return fmt.Sprintf("Successfully undo the last change of %s.", strings.Join(paths, ", ")), nil
+}
+
+// rollback brings the restored files back to their state after the change,
+// files are restored from the last one, so they are rolled back from the
+// first.
+func (us *obsidian) rollback(ctx context.Context, restored []fileChange) {
+	for _, fc := range restored {
+		if err := us.restoreFile(ctx, reverseChange(fc)); err != nil {
+			log.Errorf("roll back undo [filepath = %q]: %v", fc.Path, err)
+		}
+	}
+}
+
+// reverseChange returns the change which restoreFile undoes by redoing fc.
+func reverseChange(fc fileChange) fileChange {
+	switch {
+	case fc.Removed:
+		return fileChange{Path: fc.Path, After: fc.Before}
+	case fc.Existed:
+		return fileChange{Path: fc.Path, Existed: true, Before: fc.After, After: fc.Before}
+	default:
+		return fileChange{Path: fc.Path, Existed: true, Removed: true, Before: fc.After}
+	}
+}
+
+// checkFile reports errFileChanged if the file is not in the state the
+// change left it in.
+func (us *obsidian) checkFile(ctx context.Context, fc fileChange) error {
+	exist, err := us.repo(ctx).FileExist(fc.Path)
+	if err != nil {
+		return err
+	}
+
+	if fc.Removed {
+		if exist {
+			return errFileChanged
+		}
+
+		return nil
+	}
+
+	if !exist {
+		return errFileChanged
+	}
+
+	data, err := us.repo(ctx).ReadFromFile(fc.Path)
+	if err != nil {
+		return err
+	}
+
+	if data != fc.After {
+		return errFileChanged
+	}
+
+	return nil
+}
+
+// restoreFile brings the file back to its state before the change, unless
+// it was edited after the change.
+func (us *obsidian) restoreFile(ctx context.Context, fc fileChange) error {
+	switch {
+	case fc.Removed:
+		err := us.repo(ctx).WriteNewFile(fc.Path, fc.Before)
+		if errors.Is(err, os.ErrExist) {
+			return errFileChanged
+		}
+
+		return err
+	case fc.Existed:
+		return us.repo(ctx).UpdateFile(fc.Path, func(data string) (string, error) {
+			if data != fc.After {
+				return "", errFileChanged
+			}
+
+			return fc.Before, nil
+		})
+	default:
+		return us.removeCreatedFile(ctx, fc)
+	}
+}
+
+func (us *obsidian) removeCreatedFile(ctx context.Context, fc fileChange) error {
+	data, err := us.repo(ctx).ReadFromFile(fc.Path)
+	if err != nil {
+		return err
+	}
+
+	if data != fc.After {
+		return errFileChanged
+	}
+
+	return us.repo(ctx).RemoveFile(fc.Path)
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUndoChange(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Idea.md": "---\ntags: [inbox]\n---\n# Idea\n",
+	})
+	ctx := testContext()
+
+	notes, err := us.GetInboxNotes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := us.DeleteInboxNote(ctx, notes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, us.Vault.TrashDir, "Idea.md")); err != nil {
+		t.Fatalf("note is not in trash: %v", err)
+	}
+
+	if _, err := us.UndoChange(ctx, us.LastChangeID(ctx)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readTestFile(t, dir, "Idea.md"); got != "---\ntags: [inbox]\n---\n# Idea\n" {
+		t.Errorf("restored note = %q", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, us.Vault.TrashDir, "Idea.md")); !os.IsNotExist(err) {
+		t.Errorf("note is still in trash: %v", err)
+	}
+
+	if msg, _ := us.UndoChange(ctx, 0); msg != "Nothing to undo." {
+		t.Errorf("UndoChange() = %q, want nothing to undo", msg)
+	}
+}
+
+func TestUndoChangeKeepsChangedFiles(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Idea.md": "---\ntags: [inbox]\n---\n# Idea\n",
+	})
+	ctx := testContext()
+
+	notes, err := us.GetInboxNotes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := us.InboxNoteToTask(ctx, notes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	trashed := filepath.Join(us.Vault.TrashDir, "Idea.md")
+	writeTestFile(t, dir, trashed, "edited")
+
+	msg, err := us.UndoChange(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != "Can't undo, \""+trashed+"\" has changed since." {
+		t.Errorf("UndoChange() = %q", msg)
+	}
+
+	// nothing is restored partially
+	if got := readTestFile(t, dir, us.Vault.TasksList); got != "\n- [ ] Idea" {
+		t.Errorf("tasks = %q, want the task kept", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "Idea.md")); !os.IsNotExist(err) {
+		t.Errorf("note is restored: %v", err)
+	}
+
+	// the change is kept and can be undone once the file is back
+	writeTestFile(t, dir, trashed, "---\ntags: [inbox]\n---\n# Idea\n")
+	if _, err := us.UndoChange(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, us.Vault.TasksList)); !os.IsNotExist(err) {
+		t.Errorf("created tasks list is kept: %v", err)
+	}
+
+	if got := readTestFile(t, dir, "Idea.md"); got != "---\ntags: [inbox]\n---\n# Idea\n" {
+		t.Errorf("restored note = %q", got)
+	}
+}
+
+func TestUndoChangeOnlyLast(t *testing.T) {
+	us, _ := newTestObsidian(t, nil)
+	ctx := testContext()
+
+	if _, err := us.ParseMessage(ctx, "#shopping\nmilk"); err != nil {
+		t.Fatal(err)
+	}
+	first := us.LastChangeID(ctx)
+
+	if _, err := us.ParseMessage(ctx, "#shopping\nbread"); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg, _ := us.UndoChange(ctx, first); msg != "Only the last change can be undone." {
+		t.Errorf("UndoChange(first) = %q", msg)
+	}
+}
+
+func TestUndoChangeRollsBack(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Idea.md": "d",
+		"Task.md": "task",
+	})
+	ctx := testContext()
+
+	// Idea.md doesn't hold "b" once "d" is undone, the restore fails after
+	// Task.md is removed and Idea.md is restored
+	id := us.history.push(1, change{Files: []fileChange{
+		{Path: "Idea.md", Existed: true, Before: "a", After: "b"},
+		{Path: "Task.md", After: "task"},
+		{Path: "Idea.md", Existed: true, Before: "c", After: "d"},
+	}})
+
+	msg, err := us.UndoChange(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != `Can't undo, "Idea.md" has changed since.` {
+		t.Errorf("UndoChange() = %q", msg)
+	}
+
+	if got := readTestFile(t, dir, "Idea.md"); got != "d" {
+		t.Errorf("Idea.md = %q, want it rolled back", got)
+	}
+
+	if got := readTestFile(t, dir, "Task.md"); got != "task" {
+		t.Errorf("Task.md = %q, want it rolled back", got)
+	}
+
+	if got := us.LastChangeID(ctx); got != id {
+		t.Errorf("LastChangeID() = %d, want the change kept", got)
+	}
+}
@@ -0,0 +1,219 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ProgressNotStarted = "not_started"
+	ProgressInProgress = "in_progress"
+	ProgressFinished   = "finished"
+)
+
+const (
+	defaultBookTemplate = `---
+name: {{name}}
+author: {{author}}
+progress: {{progress}}
+added: {{added}}
+---
+`
+	defaultFilmTemplate = `---
+name: {{name}}
+director: {{director}}
+progress: {{progress}}
+added: {{added}}
+---
+`
+)
+
+// AddBook creates a note in the books directory, the first line of text
+// is the author.
+func (us *obsidian) AddBook(ctx context.Context, title, text string) (string, error) {
+	data := Book{
+		Name:     title,
+		Author:   firstLine(text),
+		Progress: ProgressNotStarted,
+		Added:    us.Clock.Now().Format("2006-01-02"),
+	}
+
+	return us.createLibraryNote(ctx, us.Vault.BooksDir, us.Vault.BookTemplate, defaultBookTemplate, title, data, func(note *frontmatter.Note) error {
+		return setProperties(note, "name", data.Name, "author", data.Author, "progress", data.Progress, "added", data.Added)
+	})
+}
+
+// AddFilm creates a note in the films directory, the first line of text
+// is the director.
+func (us *obsidian) AddFilm(ctx context.Context, title, text string) (string, error) {
+	data := Film{
+		Name:     title,
+		Director: firstLine(text),
+		Progress: ProgressNotStarted,
+		Added:    us.Clock.Now().Format("2006-01-02"),
+	}
+
+	return us.createLibraryNote(ctx, us.Vault.FilmsDir, us.Vault.FilmTemplate, defaultFilmTemplate, title, data, func(note *frontmatter.Note) error {
+		return setProperties(note, "name", data.Name, "director", data.Director, "progress", data.Progress, "added", data.Added)
+	})
+}
+
+// createLibraryNote creates the note from the template. Placeholders are
+// filled in the body only, properties of the template are set by the
+// properties func, so values never break the YAML.
+func (us *obsidian) createLibraryNote(ctx context.Context, dir, templatePath, defaultTemplate, title string, data any, properties func(note *frontmatter.Note) error) (string, error) {
+	name := sanitizeTitle(title)
+	if name == "" {
+		return "", fmt.Errorf("title is required")
+	}
+
+	templateContent := defaultTemplate
+	if templatePath != "" {
+		exist, err := us.repo(ctx).FileExist(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("check file exist: %w", err)
+		}
+
+		if exist {
+			templateContent, err = us.repo(ctx).ReadFromFile(templatePath)
+			if err != nil {
+				return "", fmt.Errorf("read from file: %w", err)
+			}
+		}
+	}
+
+	block, body, hasBlock := frontmatter.Split(templateContent)
+
+	tmpl, err := template.New(dir).Parse(transformPlaceholders(body))
+	if err != nil {
+		return "", fmt.Errorf("parse %q template: %w", dir, err)
+	}
+
+	outputFilePath := filepath.Join(dir, name+".md")
+
+	var content strings.Builder
+	if hasBlock {
+		content.WriteString("---\n" + placeholderRe.ReplaceAllString(block, "") + "---\n")
+	}
+
+	err = tmpl.Execute(&content, data)
+	if err != nil {
+		return "", fmt.Errorf("execute template [filepath = %q]: %w", outputFilePath, err)
+	}
+
+	rendered, err := editNote(content.String(), properties)
+	if err != nil {
+		return "", fmt.Errorf("set properties [filepath = %q]: %w", outputFilePath, err)
+	}
+
+	err = us.createFile(ctx, outputFilePath, rendered)
+	if errors.Is(err, os.ErrExist) {
+		return "Note with such name already exist.", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("create file: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully create note %q.", outputFilePath), nil
+}
+
+// setProperties sets the key and value pairs, empty values are skipped to
+// keep the placeholder of the template empty.
+func setProperties(note *frontmatter.Note, pairs ...string) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+
+		if err := note.Set(pairs[i], pairs[i+1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetProgress handles "/progress <name> <state>", it looks for the book or
+// film by its name property or file name and rewrites its progress.
+func (us *obsidian) SetProgress(ctx context.Context, msg string) (string, error) {
+	args := strings.Fields(trimCommand(msg))
+	if len(args) < 2 {
+		return "", fmt.Errorf("usage: /progress <name> <%s|%s|%s>", ProgressNotStarted, ProgressInProgress, ProgressFinished)
+	}
+
+	name := strings.Join(args[:len(args)-1], " ")
+	progress, err := normalizeProgress(args[len(args)-1])
+	if err != nil {
+		return "", err
+	}
+
+	fp, err := us.findLibraryNote(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	err = us.updateNote(ctx, fp, func(note *frontmatter.Note) error {
+		return note.Set("progress", progress)
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully set progress of %q to %s.", fp, progress), nil
+}
+
+func normalizeProgress(progress string) (string, error) {
+	progress = strings.ReplaceAll(strings.ToLower(progress), "-", "_")
+
+	switch progress {
+	case ProgressNotStarted, ProgressInProgress, ProgressFinished:
+		return progress, nil
+	}
+
+	return "", fmt.Errorf("unknown progress %q, expected %s, %s or %s", progress, ProgressNotStarted, ProgressInProgress, ProgressFinished)
+}
+
+func (us *obsidian) findLibraryNote(ctx context.Context, name string) (string, error) {
+	for _, dir := range []string{us.Vault.BooksDir, us.Vault.FilmsDir} {
+		entries, err := us.repo(ctx).ReadDir(dir)
+		if err != nil {
+			return "", fmt.Errorf("read dir: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+
+			fp := filepath.Join(dir, entry.Name())
+			if strings.EqualFold(strings.TrimSuffix(entry.Name(), ".md"), name) {
+				return fp, nil
+			}
+
+			note, err := us.readNote(ctx, fp)
+			if err != nil {
+				log.Warnf("skip note in search: %v", err)
+				continue
+			}
+
+			if strings.EqualFold(note.String("name"), name) {
+				return fp, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no book or film with name %q", name)
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
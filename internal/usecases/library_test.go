@@ -0,0 +1,108 @@
+package usecases
+
+import "testing"
+
+func TestAddBook(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+
+	if _, err := us.AddBook(testContext(), "Dune: Messiah", `Frank "Frank" Herbert`); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "---\nname: 'Dune: Messiah'\nauthor: Frank \"Frank\" Herbert\nprogress: not_started\nadded: \"2026-05-03\"\n---\n"
+	if got := readTestFile(t, dir, "Books/Dune Messiah.md"); got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+
+	note, err := us.readNote(testContext(), "Books/Dune Messiah.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if note.String("name") != "Dune: Messiah" || note.String("added") != "2026-05-03" {
+		t.Errorf("properties = %q, %q", note.String("name"), note.String("added"))
+	}
+}
+
+func TestAddFilmWithTemplate(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Bins/Templates/Film.md": "---\ntags: [film]\nname: {{name}}\ndirector: {{director}}\nrating:\n---\n# {{name}}\n\nby {{director}}\n",
+	})
+	us.Vault.FilmTemplate = "Bins/Templates/Film.md"
+
+	if _, err := us.AddFilm(testContext(), "Alien: Romulus", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "---\ntags: [film]\nname: 'Alien: Romulus'\ndirector:\nrating:\nprogress: not_started\nadded: \"2026-05-03\"\n---\n# Alien: Romulus\n\nby \n"
+	if got := readTestFile(t, dir, "Films/Alien Romulus.md"); got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+}
+
+func TestParseMessageBookTitleOnNextLine(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+
+	if _, err := us.ParseMessage(testContext(), "#book\nDune\nFrank Herbert"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "---\nname: Dune\nauthor: Frank Herbert\nprogress: not_started\nadded: \"2026-05-03\"\n---\n"
+	if got := readTestFile(t, dir, "Books/Dune.md"); got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+}
+
+func TestAddBookExisting(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Books/Dune.md": "# Dune\n",
+	})
+
+	msg, err := us.AddBook(testContext(), "Dune", "Frank Herbert")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != "Note with such name already exist." {
+		t.Errorf("AddBook() = %q", msg)
+	}
+
+	if got := readTestFile(t, dir, "Books/Dune.md"); got != "# Dune\n" {
+		t.Errorf("note = %q, want it unchanged", got)
+	}
+}
+
+func TestSetProgress(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Books/Dune Messiah.md": "---\nname: 'Dune: Messiah'\nprogress: not_started # keep\n---\n# Dune\n",
+		"Films/Alien.md":        "---\nname: Alien\n---\n",
+	})
+	ctx := testContext()
+
+	tests := []struct {
+		msg  string
+		fp   string
+		want string
+	}{
+		// by the name property, the progress is normalized
+		{msg: "/progress dune: messiah In-Progress", fp: "Books/Dune Messiah.md", want: "---\nname: 'Dune: Messiah'\nprogress: in_progress # keep\n---\n# Dune\n"},
+		// by the file name, a missing property is added
+		{msg: "/progress alien finished", fp: "Films/Alien.md", want: "---\nname: Alien\nprogress: finished\n---\n"},
+	}
+
+	for _, tt := range tests {
+		if _, err := us.SetProgress(ctx, tt.msg); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := readTestFile(t, dir, tt.fp); got != tt.want {
+			t.Errorf("%s: note = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+
+	for _, msg := range []string{"/progress alien", "/progress alien done", "/progress Solaris finished"} {
+		if _, err := us.SetProgress(ctx, msg); err == nil {
+			t.Errorf("SetProgress(%q) must fail", msg)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+)
+
+// AddLocation appends the location to today's timestamps note.
+func (us *obsidian) AddLocation(ctx context.Context, location models.Location) (string, error) {
+	entry := "📍 "
+	if place := placeName(location); place != "" {
+		entry += place + " "
+	}
+	entry += fmt.Sprintf("[%s](%s)", coordinates(location), geoURI(location))
+
+	fp, err := us.appendToTimestamps(ctx, entry)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Successfully add location to file. %s", fp), nil
+}
+
+// SetInboxNoteLocation sets the location and venue properties of the
+// inbox note.
+func (us *obsidian) SetInboxNoteLocation(ctx context.Context, id string, location models.Location) (string, error) {
+	note, err := us.GetInboxNote(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	err = us.updateNote(ctx, note.Path, func(n *frontmatter.Note) error {
+		// the "[lat, lon]" list is what map plugins of Obsidian read
+		if err := n.Set("location", []float64{location.Lat, location.Lon}); err != nil {
+			return err
+		}
+
+		if location.Venue != "" {
+			return n.Set("venue", location.Venue)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("set location: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully set location of note %q.", note.Title), nil
+}
+
+// LatestInboxNote returns the newest inbox note of the vault, nil when the
+// inbox is empty. File systems don't keep the creation time portably, so
+// the time of the last write stands for it.
+func (us *obsidian) LatestInboxNote(ctx context.Context) (*models.InboxNote, error) {
+	var (
+		latest   string
+		latestAt time.Time
+	)
+
+	err := us.walkInbox(ctx, func(fp string, entry os.DirEntry) error {
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("file info [filepath = %q]: %w", fp, err)
+		}
+
+		if latest == "" || info.ModTime().After(latestAt) {
+			latest, latestAt = fp, info.ModTime()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if latest == "" {
+		return nil, nil
+	}
+
+	note := newInboxNote(latest)
+	us.inboxIDs.add(userID(ctx), note)
+
+	return &note, nil
+}
+
+func placeName(location models.Location) string {
+	var parts []string
+	for _, part := range []string{location.Venue, location.Address} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func coordinates(location models.Location) string {
+	return fmt.Sprintf("%s, %s", formatCoordinate(location.Lat), formatCoordinate(location.Lon))
+}
+
+func geoURI(location models.Location) string {
+	return fmt.Sprintf("geo:%s,%s", formatCoordinate(location.Lat), formatCoordinate(location.Lon))
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
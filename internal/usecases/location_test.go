@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+)
+
+func TestLatestInboxNote(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+	ctx := testContext()
+
+	note, err := us.LatestInboxNote(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if note != nil {
+		t.Fatalf("LatestInboxNote() = %+v, want nil", note)
+	}
+
+	// the bot's history is not needed, notes are found in the vault
+	for i, name := range []string{"First Idea.md", "Second Idea.md", "Old.md"} {
+		writeTestFile(t, dir, name, "---\ntags: [inbox]\n---\n")
+
+		at := testNow.Add(time.Duration(i) * time.Minute)
+		if name == "Old.md" {
+			at = testNow.Add(-time.Hour)
+		}
+
+		if err := os.Chtimes(filepath.Join(dir, name), at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	note, err = us.LatestInboxNote(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if note == nil || note.Path != "Second Idea.md" {
+		t.Fatalf("LatestInboxNote() = %+v, want Second Idea.md", note)
+	}
+
+	if _, err := us.SetInboxNoteLocation(ctx, note.ID, models.Location{Lat: 1, Lon: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a sorted note is skipped
+	writeTestFile(t, dir, "Second Idea.md", "# Second Idea\n")
+
+	note, err = us.LatestInboxNote(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if note == nil || note.Path != "First Idea.md" {
+		t.Fatalf("LatestInboxNote() = %+v, want First Idea.md", note)
+	}
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+)
+
+func (us *obsidian) readNote(ctx context.Context, fp string) (*frontmatter.Note, error) {
+	data, err := us.repo(ctx).ReadFromFile(fp)
+	if err != nil {
+		return nil, fmt.Errorf("read from file: %w", err)
+	}
+
+	note, err := frontmatter.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse note [filepath = %q]: %w", fp, err)
+	}
+
+	return note, nil
+}
+
+// updateNote applies update to the properties and body of the note and
+// writes it back, recording the change for undo.
+func (us *obsidian) updateNote(ctx context.Context, fp string, update func(note *frontmatter.Note) error) error {
+	return us.updateFile(ctx, fp, func(data string) (string, error) {
+		return editNote(data, update)
+	})
+}
+
+// editNote applies update to the properties and body of the note content.
+func editNote(data string, update func(note *frontmatter.Note) error) (string, error) {
+	note, err := frontmatter.Parse(data)
+	if err != nil {
+		return "", fmt.Errorf("parse note: %w", err)
+	}
+
+	if err := update(note); err != nil {
+		return "", err
+	}
+
+	return note.Render()
+}
@@ -1,16 +1,21 @@
 package usecases
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"text/template"
-	"time"
-	_ "time/tzdata"
+
+	"github.com/r-mol/ObsidianBot/internal/clock"
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+	"github.com/r-mol/ObsidianBot/pkg/markdown"
+	"github.com/r-mol/ObsidianBot/pkg/webpage"
+	"golang.org/x/exp/slices"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -21,58 +26,116 @@ const (
 	TagInbox        Tag = "inbox"
 	TagShoppingList Tag = "shopping"
 	TagAction       Tag = "action"
-)
-
-const (
-	FilenameShoppingList = "Shopping List.md"
-	FilenameWishList     = "Wish List.md"
-	DirTimestamps        = "Timestamps"
-	DirBooks             = "Books"
-	DirFilms             = "Films"
+	TagWish         Tag = "wish"
+	TagBook         Tag = "book"
+	TagFilm         Tag = "film"
 )
 
 type Repository interface {
-	CreateFile(fp string) (*os.File, error)
 	FileExist(fp string) (bool, error)
+	DirExist(path string) (bool, error)
 	ReadFromFile(fp string) (string, error)
-	AppendToFile(fp string, data string) error
-	WriteToFile(fp string, data string) error
+	WriteNewFile(fp string, data string) error
+	UpdateFile(fp string, update func(data string) (string, error)) error
+	UpsertFile(fp string, update func(data string, exist bool) (string, error)) error
+	RemoveFile(fp string) error
+	CreateDir(path string) error
 	ReadDir(path string) ([]os.DirEntry, error)
-	OpenFile(fp string) (*os.File, error)
+}
+
+var errNoteExists = errors.New("note with such name already exists")
+
+// Transcriber turns voice notes into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio io.Reader) (string, error)
+}
+
+// Fetcher reads the title and description of web pages for clippings.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (*webpage.Page, error)
 }
 
 type obsidian struct {
-	Repo   Repository
-	UserID int64
+	Repo Repository
+	// UserRepos holds vaults of users who don't share the default one.
+	UserRepos   map[int64]Repository
+	Vault       *models.VaultConfig
+	Clock       clock.Clock
+	Transcriber Transcriber
+	Fetcher     Fetcher
+
+	history  history
+	inboxIDs inboxIDs
 }
 
-func NewObsidian(repo Repository, userID int64) *obsidian {
+func NewObsidian(repo Repository, userRepos map[int64]Repository, vault *models.VaultConfig, clk clock.Clock, transcriber Transcriber, fetcher Fetcher) *obsidian {
 	return &obsidian{
-		Repo:   repo,
-		UserID: userID,
+		Repo:        repo,
+		UserRepos:   userRepos,
+		Vault:       vault,
+		Clock:       clk,
+		Transcriber: transcriber,
+		Fetcher:     fetcher,
 	}
 }
 
-func (us *obsidian) ParseMessage(ctx context.Context, msg string) (string, error) {
-	tag, text, err := extractTagAndText(msg)
-	if err != nil {
-		if isSingleLine(msg) {
-			return us.CreateNewNoteToInbox(ctx, msg)
-		} else {
-			return "", fmt.Errorf("extract tag: %w", err)
+// repo returns the vault of the user acting in ctx.
+func (us *obsidian) repo(ctx context.Context) Repository {
+	if user, ok := models.UserFromContext(ctx); ok {
+		if repo, ok := us.UserRepos[user.ID]; ok {
+			return repo
 		}
 	}
 
+	return us.Repo
+}
+
+// userID returns the id of the user acting in ctx, zero when there is none.
+func userID(ctx context.Context) int64 {
+	if user, ok := models.UserFromContext(ctx); ok {
+		return user.ID
+	}
+
+	return 0
+}
+
+func (us *obsidian) ParseMessage(ctx context.Context, msg string) (string, error) {
+	if link, ok := parseURL(msg); ok {
+		return us.ClipURL(ctx, link)
+	}
+
+	tag, arg, text, err := extractTagAndText(msg)
+	if err != nil || !isKnownTag(tag) {
+		// the tag may be anywhere in the text, like Obsidian tags
+		tag, arg, text = extractInlineTag(msg)
+	}
+
+	if tag == "" {
+		return us.CreateNewNoteToInbox(ctx, msg)
+	}
+
 	var newMsg string
 	switch Tag(tag) {
 	case TagInbox:
-		newMsg, err = us.CreateNewNoteToInbox(ctx, text)
+		newMsg, err = us.CreateNewNoteToInbox(ctx, strings.TrimSpace(arg+"\n"+text))
 	case TagShoppingList:
-		newMsg, err = us.AddItemsToShoppingList(ctx, text)
+		// "#shopping dairy" puts the items into the "dairy" category
+		if arg != "" && strings.TrimSpace(text) != "" {
+			text = fmt.Sprintf("%s %s\n%s", strings.Repeat("#", defaultSectionLevel), arg, text)
+		}
+		newMsg, err = us.AddItemsToShoppingList(ctx, textOrArg(text, arg))
 	case TagAction:
-		newMsg, err = us.AddAction(ctx, text)
+		newMsg, err = us.AddAction(ctx, textOrArg(text, arg))
+	case TagWish:
+		newMsg, err = us.AddItemsToWishList(ctx, textOrArg(text, arg))
+	case TagBook:
+		arg, text = titleOrFirstLine(arg, text)
+		newMsg, err = us.AddBook(ctx, arg, text)
+	case TagFilm:
+		arg, text = titleOrFirstLine(arg, text)
+		newMsg, err = us.AddFilm(ctx, arg, text)
 	default:
-		return "", fmt.Errorf("unknown tag [tag = %q]: %w", tag, err)
+		return "", fmt.Errorf("unknown tag [tag = %q]", tag)
 	}
 	if err != nil {
 		return "", fmt.Errorf("execute usecase for [tag = %q]: %w", tag, err)
@@ -81,338 +144,338 @@ func (us *obsidian) ParseMessage(ctx context.Context, msg string) (string, error
 	return newMsg, nil
 }
 
-func (us *obsidian) CreateNewNoteToInbox(ctx context.Context, msg string) (string, error) {
-	templateContent, err := us.Repo.ReadFromFile(FilePathInboxTemplate)
-	if err != nil {
-		return "", fmt.Errorf("read from file: %w", err)
-	}
+// extractInlineTag finds a known tag on the first line of the message or
+// standing alone on a line outside of code blocks, so tags mentioned in the
+// middle of a long note don't re-route it. The message is split without the
+// tag the way extractTagAndText does: books and films take the first line as
+// the argument, other tags take the whole text.
+func extractInlineTag(msg string) (string, string, string) {
+	title, _, _ := strings.Cut(msg, "\n")
 
-	templateContent = transformPlaceholders(templateContent)
+	candidates := markdown.Tags(title)
+	candidates = append(candidates, standaloneTags(msg)...)
 
-	msg = strings.Title(msg)
-	data := Inbox{
-		Title: msg,
-	}
+	for _, tag := range candidates {
+		tag = strings.ToLower(tag)
+		if !isKnownTag(tag) {
+			continue
+		}
 
-	tmpl, err := template.New("inbox").Parse(templateContent)
-	if err != nil {
-		return "", fmt.Errorf("parse \"inbox\" template: %w", err)
+		text := strings.TrimSpace(markdown.RemoveTag(msg, tag))
+		if Tag(tag) == TagBook || Tag(tag) == TagFilm {
+			arg, rest, _ := strings.Cut(text, "\n")
+			return tag, strings.TrimSpace(arg), rest
+		}
+
+		return tag, "", text
 	}
 
-	outputFilePath := fmt.Sprintf("%s.md", msg)
+	return "", "", ""
+}
 
-	exist, err := us.Repo.FileExist(outputFilePath)
-	if err != nil {
-		return "", fmt.Errorf("check file exist: %w", err)
-	}
+// standaloneTags returns tags written alone on their line, like "#shopping"
+// under a list of items. Lines of code blocks are skipped.
+func standaloneTags(msg string) []string {
+	_, tags := cutStandaloneTags(msg)
+	return tags
+}
 
-	if exist {
-		return "Note with such name already exist.", nil
-	}
+// cutStandaloneTags removes the lines holding a single tag from the text
+// and returns their tags. Lines of code blocks are kept.
+func cutStandaloneTags(msg string) (string, []string) {
+	var lines, tags []string
 
-	outputFile, err := us.Repo.CreateFile(outputFilePath)
-	if err != nil {
-		return "", fmt.Errorf("create file: %w", err)
+	var fence string
+	for _, line := range strings.Split(msg, "\n") {
+		trimmed := strings.TrimSpace(line)
 
-	}
-	defer outputFile.Close()
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		default:
+			if lineTags := markdown.Tags(trimmed); len(lineTags) == 1 && trimmed == "#"+lineTags[0] {
+				tags = append(tags, lineTags[0])
+				continue
+			}
+		}
 
-	err = tmpl.Execute(outputFile, data)
-	if err != nil {
-		return "", fmt.Errorf("execute template writing to file [filepath = %q]: %w", outputFilePath, err)
+		lines = append(lines, line)
 	}
 
-	return fmt.Sprintf("Successfully create note %q with inbox tag.", msg), nil
+	return strings.Join(lines, "\n"), tags
 }
 
-func (us *obsidian) AddAction(ctx context.Context, msg string) (string, error) {
-	moscowLocation, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		return "", fmt.Errorf("load Moscow location: %w", err)
+func isKnownTag(tag string) bool {
+	switch Tag(tag) {
+	case TagInbox, TagShoppingList, TagAction, TagWish, TagBook, TagFilm:
+		return true
 	}
 
-	currentTime := time.Now().In(moscowLocation)
-
-	content := fmt.Sprintf("\n%s - %s", currentTime.Format("15:04"), msg)
-
-	filename := fmt.Sprintf("%s.md", currentTime.Format("2006-01-02"))
-	fp := filepath.Join(DirTimestamps, filename)
+	return false
+}
 
-	err = us.Repo.AppendToFile(fp, content)
-	if err != nil {
-		return "", fmt.Errorf("append to file: %w", err)
+// textOrArg allows single-line messages like "#action went for a run".
+func textOrArg(text, arg string) string {
+	if strings.TrimSpace(text) == "" {
+		return arg
 	}
 
-	return fmt.Sprintf("Successfully add action to file. %s", fp), nil
+	return text
 }
 
-func (us *obsidian) GetWishList(ctx context.Context, msg string) (string, error) {
-	data, err := us.Repo.ReadFromFile(FilenameWishList)
-	if err != nil {
-		return "", fmt.Errorf("read from file: %w", err)
-	}
-
-	if data == "" {
-		return "Wish list is empty!", nil
+// titleOrFirstLine allows the title of a book or film on the line after the
+// tag, like "#book\nDune\nFrank Herbert".
+func titleOrFirstLine(arg, text string) (string, string) {
+	if strings.TrimSpace(arg) != "" {
+		return arg, text
 	}
 
-	return data, nil
-}
-
-func (us *obsidian) GetReadingList(ctx context.Context, msg string) (string, error) {
-	return us.generateReport(DirBooks)
-}
+	title, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
 
-func (us *obsidian) GetWatchingList(ctx context.Context, msg string) (string, error) {
-	return us.generateReport(DirFilms)
+	return strings.TrimSpace(title), rest
 }
 
-func (us *obsidian) generateReport(path string) (string, error) {
-	var notStarted, inProgress, finished []string
+// CreateNewNoteToInbox creates an inbox note titled by the first line of
+// the message with the rest as its content. Tags of the message are added
+// to the tags property, the content keeps them except tags standing alone
+// on a line, tags of the title are removed from the title.
+func (us *obsidian) CreateNewNoteToInbox(ctx context.Context, msg string) (string, error) {
+	title, content, _ := strings.Cut(strings.TrimSpace(msg), "\n")
 
-	entries, err := us.Repo.ReadDir(path)
-	if err != nil {
-		return "", fmt.Errorf("read dir: %w", err)
+	tags := markdown.Tags(title + "\n" + content)
+	for _, tag := range markdown.Tags(title) {
+		title = markdown.RemoveTag(title, tag)
 	}
+	content, _ = cutStandaloneTags(content)
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		if strings.HasSuffix(entry.Name(), ".md") {
-			fp := filepath.Join(path, entry.Name())
-			file, err := us.Repo.OpenFile(fp)
-			if err != nil {
-				return "", fmt.Errorf("open file: %w", err)
-			}
-			defer file.Close()
-
-			var findProperties bool
-			var name, progress string
-			scanner := bufio.NewScanner(file)
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-
-				if line == "---" {
-					if findProperties {
-						break
-					} else {
-						findProperties = true
-						continue
-					}
-				}
-
-				if strings.HasPrefix(line, "name:") {
-					name = strings.TrimSpace(strings.TrimPrefix(line, "name:"))
-				}
-
-				if strings.HasPrefix(line, "progress:") {
-					progress = strings.TrimSpace(strings.TrimPrefix(line, "progress:"))
+	var properties func(note *frontmatter.Note) error
+	if len(tags) > 0 {
+		properties = func(note *frontmatter.Note) error {
+			for _, tag := range tags {
+				if err := addTag(note, tag); err != nil {
+					return err
 				}
 			}
 
-			if err := scanner.Err(); err != nil {
-				return "", fmt.Errorf("scan: %w", err)
-			}
-
-			switch progress {
-			case "not_started":
-				notStarted = append(notStarted, name)
-			case "in_progress":
-				inProgress = append(inProgress, name)
-			case "finished":
-				finished = append(finished, name)
-			}
-
-			file.Close()
+			return nil
 		}
 	}
 
-	var report strings.Builder
-
-	report.WriteString("**Not Started**\n-------------\n\n")
-	for _, item := range notStarted {
-		report.WriteString(fmt.Sprintf("- %s\n", item))
-	}
+	return us.createInboxNote(ctx, strings.TrimSpace(title), strings.TrimSpace(content), properties)
+}
 
-	report.WriteString("\n**In Progress**\n-------------\n\n")
-	for _, item := range inProgress {
-		report.WriteString(fmt.Sprintf("- %s\n", item))
+// createInboxNote creates the note from the inbox template, the body is
+// rendered into {{content}} or appended after templates without it.
+// Properties, if not nil, adds properties to the rendered note.
+func (us *obsidian) createInboxNote(ctx context.Context, msg, body string, properties func(note *frontmatter.Note) error) (string, error) {
+	title, err := us.writeInboxNote(ctx, msg, body, properties)
+	if errors.Is(err, errNoteExists) {
+		return "Note with such name already exist.", nil
 	}
-
-	report.WriteString("\n**Finished**\n-------------\n\n")
-	for _, item := range finished {
-		report.WriteString(fmt.Sprintf("- %s\n", item))
+	if err != nil {
+		return "", err
 	}
 
-	log.Warn(report.String())
-	return report.String(), nil
+	return fmt.Sprintf("Successfully create note %q with inbox tag.", title), nil
 }
 
-func (us *obsidian) GetInboxItems(ctx context.Context, msg string) (string, error) {
-	var items []string
-
-	entries, err := us.Repo.ReadDir("")
+// writeInboxNote is createInboxNote returning the title of the created
+// note, it fails with errNoteExists if the vault has a note with the title.
+func (us *obsidian) writeInboxNote(ctx context.Context, msg, body string, properties func(note *frontmatter.Note) error) (string, error) {
+	templateContent, err := us.repo(ctx).ReadFromFile(us.Vault.InboxTemplate)
 	if err != nil {
-		return "", fmt.Errorf("read dir: %w", err)
+		return "", fmt.Errorf("read from file: %w", err)
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		if strings.HasSuffix(entry.Name(), ".md") {
-			data, err := us.Repo.ReadFromFile(entry.Name())
-			if err != nil {
-				return "", fmt.Errorf("read file: %w", err)
-			}
+	templateContent = transformPlaceholders(templateContent)
 
-			tag := fmt.Sprintf("#%s", TagInbox)
-			if strings.Contains(data, tag) {
-				if entry.Name() != "README.md" && entry.Name() != "Inbox Notes.md" {
-					item := strings.TrimSuffix(entry.Name(), ".md")
-					items = append(items, item)
-				}
-			}
-		}
+	msg = sanitizeTitle(strings.Title(msg))
+	if msg == "" {
+		return "", fmt.Errorf("note title is empty after removing forbidden characters")
 	}
 
-	var report strings.Builder
-
-	report.WriteString("\n**Inbox**\n-------------\n\n")
-	for _, item := range items {
-		report.WriteString(fmt.Sprintf("- %s\n", item))
+	data := Inbox{
+		Title:   msg,
+		Content: body,
 	}
 
-	return report.String(), nil
-}
+	tmpl, err := template.New("inbox").Parse(templateContent)
+	if err != nil {
+		return "", fmt.Errorf("parse \"inbox\" template: %w", err)
+	}
 
-// ---------------------------------------- Shopping ----------------------------------------
+	outputFilePath := fmt.Sprintf("%s.md", msg)
 
-func (us *obsidian) GetShoppingList(ctx context.Context, msg string) (string, error) {
-	data, err := us.Repo.ReadFromFile(FilenameShoppingList)
+	exist, err := us.repo(ctx).FileExist(outputFilePath)
 	if err != nil {
-		return "", fmt.Errorf("read from file: %w", err)
+		return "", fmt.Errorf("check file exist: %w", err)
+	}
+
+	if exist {
+		return "", errNoteExists
 	}
 
-	items, err := extractItems(data)
+	var content strings.Builder
+	err = tmpl.Execute(&content, data)
 	if err != nil {
-		return "", fmt.Errorf("extract items to slice: %w", err)
+		return "", fmt.Errorf("execute template [filepath = %q]: %w", outputFilePath, err)
 	}
 
-	if len(items) == 0 {
-		return "Shopping list is empty!", nil
+	if body != "" && !strings.Contains(templateContent, "{{.Content}}") {
+		content.WriteString("\n" + body + "\n")
 	}
 
-	var content string
-	for i, item := range items {
-		item = strings.TrimPrefix(item, "-")
-		item = strings.TrimSpace(item)
+	rendered := content.String()
+	if properties != nil {
+		rendered, err = editNote(rendered, properties)
+		if err != nil {
+			return "", fmt.Errorf("set properties [filepath = %q]: %w", outputFilePath, err)
+		}
+	}
 
-		content += fmt.Sprintf("%d. %s\n", i+1, item)
+	err = us.createFile(ctx, outputFilePath, rendered)
+	if errors.Is(err, os.ErrExist) {
+		return "", errNoteExists
+	}
+	if err != nil {
+		return "", fmt.Errorf("create file: %w", err)
 	}
 
-	return content, nil
+	return msg, nil
 }
 
-func (us *obsidian) AddItemsToShoppingList(ctx context.Context, msg string) (string, error) {
-	data, err := us.Repo.ReadFromFile(FilenameShoppingList)
+func (us *obsidian) AddAction(ctx context.Context, msg string) (string, error) {
+	fp, err := us.appendToTimestamps(ctx, msg)
 	if err != nil {
-		return "", fmt.Errorf("read from file: %w", err)
+		return "", err
 	}
 
-	oldItems, err := extractItems(data)
-	if err != nil {
-		return "", fmt.Errorf("extract old items to slice: %w", err)
-	}
+	return fmt.Sprintf("Successfully add action to file. %s", fp), nil
+}
 
-	newItems, err := extractItems(msg)
-	if err != nil {
-		return "", fmt.Errorf("extract new items to slice: %w", err)
-	}
+// appendToTimestamps appends the timestamped entry to today's note and
+// returns its path.
+func (us *obsidian) appendToTimestamps(ctx context.Context, msg string) (string, error) {
+	currentTime := us.Clock.Now()
 
-	items := append(oldItems, newItems...)
+	content := fmt.Sprintf("\n%s - %s", currentTime.Format("15:04"), msg)
 
-	updatedContent := strings.Join(items, "\n")
+	filename := fmt.Sprintf("%s.md", currentTime.Format("2006-01-02"))
+	fp := filepath.Join(us.Vault.TimestampsDir, filename)
 
-	err = us.Repo.WriteToFile(FilenameShoppingList, updatedContent)
+	err := us.appendToNote(ctx, fp, content)
 	if err != nil {
-		return "", fmt.Errorf("write to file: %w", err)
+		return "", fmt.Errorf("append to file: %w", err)
 	}
 
-	return "Successfully add items to shopping list. You can check it by /shopping_list", nil
+	return fp, nil
 }
 
-func (us *obsidian) ClearShoppingList(ctx context.Context, msg string) (string, error) {
-	err := us.Repo.WriteToFile(FilenameShoppingList, "")
-	if err != nil {
-		return "", fmt.Errorf("write to file: %w", err)
-	}
+// appendToNote appends content to the note, creating the note if needed.
+func (us *obsidian) appendToNote(ctx context.Context, fp, content string) error {
+	return us.upsertFile(ctx, fp, func(data string) (string, error) {
+		return data + content, nil
+	})
+}
 
-	return "Successfully clear shopping list.", nil
+// isInboxNote reports whether the note is tagged with #inbox or a tag nested
+// into it in its properties or body.
+func isInboxNote(note *frontmatter.Note) bool {
+	return markdown.HasTag(note.Tags(), string(TagInbox)) || markdown.HasTag(markdown.Tags(note.Body), string(TagInbox))
 }
 
-func (us *obsidian) RemoveItemsFromShoppingList(ctx context.Context, msg string) (string, error) {
-	msg = strings.TrimSpace(strings.TrimPrefix(msg, "/remove_item"))
-	if msg == "" {
-		return "", fmt.Errorf("should be provided minimum one id")
-	}
+// inboxNotes walks the whole vault and returns paths of inbox notes, the
+// excluded notes and folders are skipped.
+func (us *obsidian) inboxNotes(ctx context.Context) ([]string, error) {
+	var notes []string
 
-	args := strings.Split(msg, ",")
-	if len(args) < 1 {
-		return "", fmt.Errorf("should be provided minimum one id")
+	err := us.walkInbox(ctx, func(fp string, _ os.DirEntry) error {
+		notes = append(notes, fp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var ids = make([]int, len(args))
-	for i, arg := range args {
-		arg = strings.TrimSpace(arg)
+	return notes, nil
+}
 
-		id, err := strconv.Atoi(arg)
+// walkInbox calls fn for every inbox note of the vault, the excluded notes
+// and folders are skipped.
+func (us *obsidian) walkInbox(ctx context.Context, fn func(fp string, entry os.DirEntry) error) error {
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := us.repo(ctx).ReadDir(dir)
 		if err != nil {
-			return "", fmt.Errorf("convert string to int [id = %q]: %w", arg, err)
+			return fmt.Errorf("read dir [path = %q]: %w", dir, err)
 		}
 
-		ids[i] = id - 1
-	}
+		for _, entry := range entries {
+			fp := filepath.Join(dir, entry.Name())
 
-	data, err := us.Repo.ReadFromFile(FilenameShoppingList)
-	if err != nil {
-		return "", fmt.Errorf("read from file: %w", err)
-	}
+			if entry.IsDir() {
+				if strings.HasPrefix(entry.Name(), ".") || fp == us.Vault.TrashDir ||
+					slices.Contains(us.Vault.InboxExcludedDirs, fp) {
+					continue
+				}
 
-	items, err := extractItems(data)
-	if err != nil {
-		return "", fmt.Errorf("extract items to slice: %w", err)
-	}
+				if err := walk(fp); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if !strings.HasSuffix(entry.Name(), ".md") || fp == us.Vault.InboxTemplate ||
+				slices.Contains(us.Vault.InboxExcluded, fp) {
+				continue
+			}
+
+			data, err := us.repo(ctx).ReadFromFile(fp)
+			if err != nil {
+				return fmt.Errorf("read file: %w", err)
+			}
+
+			note, err := frontmatter.Parse(data)
+			if err != nil {
+				log.Warnf("skip note in inbox, parse [filepath = %q]: %v", fp, err)
+				continue
+			}
+
+			if !isInboxNote(note) {
+				continue
+			}
 
-	for _, id := range ids {
-		if id < 0 || id >= len(items) {
-			return "", fmt.Errorf("invalid line index: %d", id+1)
+			if err := fn(fp, entry); err != nil {
+				return err
+			}
 		}
-	}
 
-	var removedItems = make([]string, len(ids))
-	for i, id := range ids {
-		removedItems[i] = items[id]
+		return nil
 	}
 
-	for i, id := range ids {
-		id -= i
-		items = append(items[:id], items[id+1:]...)
+	return walk("")
+}
+
+func (us *obsidian) GetInboxItems(ctx context.Context, msg string) (string, error) {
+	notes, err := us.inboxNotes(ctx)
+	if err != nil {
+		return "", err
 	}
 
-	updatedContent := strings.Join(items, "\n")
+	var report strings.Builder
 
-	err = us.Repo.WriteToFile(FilenameShoppingList, updatedContent)
-	if err != nil {
-		return "", fmt.Errorf("write to file: %w", err)
+	report.WriteString("\n**Inbox**\n-------------\n\n")
+	for _, fp := range notes {
+		report.WriteString(fmt.Sprintf("- %s\n", strings.TrimSuffix(fp, ".md")))
 	}
 
-	return fmt.Sprintf("Successfully delete items from shopping list. Items:\n\n%s", strings.Join(removedItems, "\n")), nil
+	return report.String(), nil
 }
 
-func (us *obsidian) RememberAboutInbox(ctx context.Context) string {
-	return "👉🏼 **Please** _sort inbox_ 👈🏼"
+func (us *obsidian) RememberAboutInbox(ctx context.Context, msg string) (string, error) {
+	return "👉🏼 **Please** _sort inbox_ 👈🏼", nil
 }
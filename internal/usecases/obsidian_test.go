@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/r-mol/ObsidianBot/internal/clock"
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"github.com/r-mol/ObsidianBot/internal/repository"
+)
+
+// testNow is the frozen time of usecases under test.
+var testNow = time.Date(2026, time.May, 3, 10, 0, 0, 0, time.UTC)
+
+// newTestObsidian returns the usecase over a temporary vault with the
+// files and the default layout.
+func newTestObsidian(t *testing.T, files map[string]string) (*obsidian, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	vault := models.DefaultVaultConfig()
+
+	for _, path := range []string{vault.TimestampsDir, vault.BooksDir, vault.FilmsDir, filepath.Dir(vault.InboxTemplate)} {
+		if err := os.MkdirAll(filepath.Join(dir, path), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defaults := map[string]string{
+		vault.InboxTemplate: "---\ntags: [inbox]\n---\n# {{title}}\n",
+		vault.ShoppingList:  "",
+		vault.WishList:      "",
+	}
+
+	for fp, data := range defaults {
+		if _, ok := files[fp]; !ok {
+			writeTestFile(t, dir, fp, data)
+		}
+	}
+
+	for fp, data := range files {
+		writeTestFile(t, dir, fp, data)
+	}
+
+	us := NewObsidian(repository.New(dir, ""), nil, vault, clock.Frozen{Time: testNow}, nil, nil)
+
+	return us, dir
+}
+
+func writeTestFile(t *testing.T, dir, fp, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, fp)), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, fp), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, dir, fp string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, fp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func testContext() context.Context {
+	return models.ContextWithUser(context.Background(), &models.User{ID: 1, Name: "test", Role: models.RoleOwner})
+}
+
+func TestExtractInlineTag(t *testing.T) {
+	tests := []struct {
+		msg            string
+		tag, arg, text string
+	}{
+		{msg: "milk #shopping", tag: "shopping", text: "milk"},
+		{msg: "milk\nbread\n#shopping", tag: "shopping", text: "milk\nbread"},
+		{msg: "Dune #book\nFrank Herbert", tag: "book", arg: "Dune", text: "Frank Herbert"},
+		{msg: "Meeting notes\nwe talked a lot\nI should read Dune #book"},
+		{msg: "Snippet\n```\n#shopping\n```"},
+		{msg: "just text #idea"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			tag, arg, text := extractInlineTag(tt.msg)
+			if tag != tt.tag || arg != tt.arg || text != tt.text {
+				t.Errorf("extractInlineTag(%q) = %q, %q, %q, want %q, %q, %q", tt.msg, tag, arg, text, tt.tag, tt.arg, tt.text)
+			}
+		})
+	}
+}
+
+func TestParseMessageKeepsInlineTagsInInbox(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+
+	if _, err := us.ParseMessage(testContext(), "Meeting notes #work\nThis #idea is great, read Dune #book\n#later"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "---\ntags:\n  - inbox\n  - work\n  - idea\n  - book\n  - later\n---\n# Meeting Notes\n\nThis #idea is great, read Dune #book\n"
+	if got := readTestFile(t, dir, "Meeting Notes.md"); got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+}
+
+func TestTimestampsUseClockLocation(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+
+	// late evening in UTC is already the next day in Moscow
+	msk := time.FixedZone("MSK", 3*60*60)
+	us.Clock = clock.Frozen{Time: time.Date(2026, time.May, 3, 23, 30, 0, 0, time.UTC).In(msk)}
+
+	if _, err := us.ParseMessage(testContext(), "#action went for a run"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readTestFile(t, dir, "Timestamps/2026-05-04.md"); got != "\n02:30 - went for a run" {
+		t.Errorf("timestamps = %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	amountPattern = `(\d+(?:[.,]\d+)?)`
+	unitPattern   = `(kg|g|mg|l|ml|pcs|pc)`
+)
+
+var (
+	// "2 milk", "500g cheese", "1.5 l milk"
+	amountFirstRe = regexp.MustCompile(`(?i)^` + amountPattern + `\s*(?:` + unitPattern + `\b)?\s+(.+)$`)
+	// "milk x3", "milk ×3"
+	timesLastRe = regexp.MustCompile(`(?i)^(.+?)\s+[x×]\s*` + amountPattern + `$`)
+	// "milk 2", "milk 2l"
+	amountLastRe = regexp.MustCompile(`(?i)^(.+?)\s+` + amountPattern + `\s*` + unitPattern + `?$`)
+)
+
+// quantity is a shopping item split into its name and amount. Items
+// without an explicit amount count as one piece.
+type quantity struct {
+	Name      string
+	Amount    float64
+	Unit      string
+	HasAmount bool
+}
+
+func parseQuantity(text string) quantity {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if match := amountFirstRe.FindStringSubmatch(text); match != nil {
+		return newQuantity(match[3], match[1], match[2])
+	}
+
+	if match := timesLastRe.FindStringSubmatch(text); match != nil {
+		return newQuantity(match[1], match[2], "")
+	}
+
+	if match := amountLastRe.FindStringSubmatch(text); match != nil {
+		return newQuantity(match[1], match[2], match[3])
+	}
+
+	return quantity{Name: text, Amount: 1}
+}
+
+func newQuantity(name, amount, unit string) quantity {
+	value, err := strconv.ParseFloat(strings.Replace(amount, ",", ".", 1), 64)
+	if err != nil {
+		return quantity{Name: name, Amount: 1}
+	}
+
+	return quantity{
+		Name:      name,
+		Amount:    value,
+		Unit:      strings.ToLower(unit),
+		HasAmount: true,
+	}
+}
+
+// key identifies items which are the same up to case, whitespace and
+// simple plural forms.
+func (q quantity) key() string {
+	return normalizeWords(q.Name)
+}
+
+// merge sums amounts of the same items measured in the same unit.
+func (q quantity) merge(other quantity) (quantity, bool) {
+	if q.key() != other.key() || q.Unit != other.Unit {
+		return q, false
+	}
+
+	q.Amount += other.Amount
+	q.HasAmount = true
+
+	return q, true
+}
+
+func (q quantity) String() string {
+	if !q.HasAmount {
+		return q.Name
+	}
+
+	amount := strconv.FormatFloat(q.Amount, 'f', -1, 64)
+	if q.Unit == "" {
+		return amount + " " + q.Name
+	}
+
+	return amount + q.Unit + " " + q.Name
+}
@@ -0,0 +1,41 @@
+package usecases
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		text string
+		want quantity
+	}{
+		{"milk", quantity{Name: "milk", Amount: 1}},
+		{"2 milk", quantity{Name: "milk", Amount: 2, HasAmount: true}},
+		{"500g cheese", quantity{Name: "cheese", Amount: 500, Unit: "g", HasAmount: true}},
+		{"1,5 l milk", quantity{Name: "milk", Amount: 1.5, Unit: "l", HasAmount: true}},
+		{"milk x3", quantity{Name: "milk", Amount: 3, HasAmount: true}},
+		{"milk × 3", quantity{Name: "milk", Amount: 3, HasAmount: true}},
+		{"milk 2l", quantity{Name: "milk", Amount: 2, Unit: "l", HasAmount: true}},
+		{"box 2", quantity{Name: "box", Amount: 2, HasAmount: true}},
+		{"wax 3", quantity{Name: "wax", Amount: 3, HasAmount: true}},
+		{"box x2", quantity{Name: "box", Amount: 2, HasAmount: true}},
+		{"  green   tea  ", quantity{Name: "green tea", Amount: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := parseQuantity(tt.text); got != tt.want {
+				t.Errorf("parseQuantity(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantityMerge(t *testing.T) {
+	merged, ok := parseQuantity("2 apples").merge(parseQuantity("apple x3"))
+	if !ok || merged.String() != "5 apples" {
+		t.Errorf("merge = %q, %v, want \"5 apples\", true", merged.String(), ok)
+	}
+
+	if _, ok := parseQuantity("1l milk").merge(parseQuantity("2 milk")); ok {
+		t.Error("items in different units must not merge")
+	}
+}
@@ -0,0 +1,306 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var dateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"}
+
+// libraryItem is a book or film note with its optional properties: rating,
+// started and finished dates, pages for books and duration for films.
+type libraryItem struct {
+	Name      string
+	Progress  string
+	Rating    float64
+	HasRating bool
+	Added     time.Time
+	Started   time.Time
+	Finished  time.Time
+	Pages     int
+	Duration  string
+}
+
+// Date is the date the item is filtered by year with, it depends on the
+// progress: finished items by the finished date, items in progress by the
+// started date and others by the added date. It is zero when unknown.
+func (i libraryItem) Date() time.Time {
+	switch i.Progress {
+	case ProgressFinished:
+		return i.Finished
+	case ProgressInProgress:
+		return i.Started
+	}
+
+	return i.Added
+}
+
+// libraryFilter is parsed from the arguments of /reading_list and
+// /watching_list: "status:finished year:2024 rating:4". Bare statuses and
+// years are accepted too: "/reading_list finished 2024".
+type libraryFilter struct {
+	Status    string
+	Year      int
+	MinRating float64
+}
+
+func parseLibraryFilter(msg string) (libraryFilter, error) {
+	var filter libraryFilter
+
+	for _, arg := range strings.Fields(trimCommand(msg)) {
+		key, value, found := strings.Cut(arg, ":")
+		if !found {
+			key, value = "", arg
+		}
+
+		switch key {
+		case "status":
+			progress, err := normalizeProgress(value)
+			if err != nil {
+				return filter, err
+			}
+			filter.Status = progress
+		case "year":
+			year, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("parse year [year = %q]: %w", value, err)
+			}
+			filter.Year = year
+		case "rating":
+			rating, err := strconv.ParseFloat(strings.TrimSuffix(value, "+"), 64)
+			if err != nil {
+				return filter, fmt.Errorf("parse rating [rating = %q]: %w", value, err)
+			}
+			filter.MinRating = rating
+		case "":
+			if year, err := strconv.Atoi(value); err == nil && len(value) == 4 {
+				filter.Year = year
+			} else if progress, err := normalizeProgress(value); err == nil {
+				filter.Status = progress
+			} else {
+				return filter, fmt.Errorf("unknown filter %q, use status:, year: or rating:", arg)
+			}
+		default:
+			return filter, fmt.Errorf("unknown filter %q, use status:, year: or rating:", arg)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f libraryFilter) match(item libraryItem) bool {
+	switch {
+	case f.Status != "" && item.Progress != f.Status:
+		return false
+	case f.Year != 0 && (item.Date().IsZero() || item.Date().Year() != f.Year):
+		return false
+	case f.MinRating != 0 && (!item.HasRating || item.Rating < f.MinRating):
+		return false
+	}
+
+	return true
+}
+
+func (us *obsidian) GetReadingList(ctx context.Context, msg string) (string, error) {
+	return us.generateReport(ctx, us.Vault.BooksDir, msg)
+}
+
+func (us *obsidian) GetWatchingList(ctx context.Context, msg string) (string, error) {
+	return us.generateReport(ctx, us.Vault.FilmsDir, msg)
+}
+
+// readLibrary reads book or film notes of the directory, notes with broken
+// properties are skipped.
+func (us *obsidian) readLibrary(ctx context.Context, path string) ([]libraryItem, error) {
+	entries, err := us.repo(ctx).ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("read dir: %w", err)
+	}
+
+	var items []libraryItem
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+
+		fp := filepath.Join(path, entry.Name())
+		note, err := us.readNote(ctx, fp)
+		if err != nil {
+			log.Warnf("skip note in report: %v", err)
+			continue
+		}
+
+		item := libraryItem{
+			Name:     note.String("name"),
+			Progress: note.String("progress"),
+			Added:    parseDate(note.String("added"), us.Clock.Location()),
+			Started:  parseDate(note.String("started"), us.Clock.Location()),
+			Finished: parseDate(note.String("finished"), us.Clock.Location()),
+			Duration: note.String("duration"),
+		}
+
+		if item.Name == "" {
+			item.Name = strings.TrimSuffix(entry.Name(), ".md")
+		}
+
+		if rating, err := strconv.ParseFloat(note.String("rating"), 64); err == nil {
+			item.Rating, item.HasRating = rating, true
+		}
+
+		if pages, err := strconv.Atoi(note.String("pages")); err == nil {
+			item.Pages = pages
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseDate(value string, location *time.Location) time.Time {
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date
+		}
+	}
+
+	return time.Time{}
+}
+
+func (us *obsidian) generateReport(ctx context.Context, path, msg string) (string, error) {
+	filter, err := parseLibraryFilter(msg)
+	if err != nil {
+		return "", err
+	}
+
+	items, err := us.readLibrary(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	var notStarted, inProgress, finished []string
+	for _, item := range items {
+		if !filter.match(item) {
+			continue
+		}
+
+		switch item.Progress {
+		case ProgressNotStarted:
+			notStarted = append(notStarted, item.Name)
+		case ProgressInProgress:
+			inProgress = append(inProgress, reportLine(item, item.Started, "started"))
+		case ProgressFinished:
+			finished = append(finished, reportLine(item, item.Finished, "finished"))
+		}
+	}
+
+	var report strings.Builder
+
+	sections := []struct {
+		title  string
+		status string
+		items  []string
+	}{
+		{"Not Started", ProgressNotStarted, notStarted},
+		{"In Progress", ProgressInProgress, inProgress},
+		{"Finished", ProgressFinished, finished},
+	}
+
+	for _, section := range sections {
+		if filter.Status != "" && filter.Status != section.status {
+			continue
+		}
+
+		report.WriteString(fmt.Sprintf("\n**%s**\n-------------\n\n", section.title))
+		for _, item := range section.items {
+			report.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+	}
+
+	return strings.TrimPrefix(report.String(), "\n"), nil
+}
+
+func reportLine(item libraryItem, date time.Time, label string) string {
+	line := item.Name
+	if item.HasRating {
+		line += fmt.Sprintf(" ⭐ %s", strconv.FormatFloat(item.Rating, 'f', -1, 64))
+	}
+
+	if item.Duration != "" {
+		line += fmt.Sprintf(" ⏱ %s", item.Duration)
+	}
+
+	if !date.IsZero() {
+		line += fmt.Sprintf(" (%s %s)", label, date.Format("2006-01-02"))
+	}
+
+	return line
+}
+
+// GetReadingStats shows books finished per month this year, their average
+// rating and pages read. Finished books without the finished date can't be
+// put into a month, so they are only counted.
+func (us *obsidian) GetReadingStats(ctx context.Context, msg string) (string, error) {
+	items, err := us.readLibrary(ctx, us.Vault.BooksDir)
+	if err != nil {
+		return "", err
+	}
+
+	now := us.Clock.Now()
+
+	var perMonth [12]int
+	var pages, rated, finished, undated int
+	var ratingSum float64
+	for _, item := range items {
+		if item.Progress != ProgressFinished {
+			continue
+		}
+
+		if item.Finished.IsZero() {
+			undated++
+			continue
+		}
+
+		if item.Finished.Year() != now.Year() {
+			continue
+		}
+
+		finished++
+		perMonth[item.Finished.Month()-1]++
+		pages += item.Pages
+
+		if item.HasRating {
+			rated++
+			ratingSum += item.Rating
+		}
+	}
+
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("**Reading stats %d**\n-------------\n\n", now.Year()))
+
+	for month := time.January; month <= now.Month(); month++ {
+		report.WriteString(fmt.Sprintf("- %s: %d\n", month.String()[:3], perMonth[month-1]))
+	}
+
+	report.WriteString(fmt.Sprintf("\nFinished: %d\n", finished))
+	if rated > 0 {
+		average := math.Round(ratingSum/float64(rated)*10) / 10
+		report.WriteString(fmt.Sprintf("Average rating: %s (%d rated)\n", strconv.FormatFloat(average, 'f', -1, 64), rated))
+	} else {
+		report.WriteString("Average rating: -\n")
+	}
+	report.WriteString(fmt.Sprintf("Pages read: %d\n", pages))
+
+	if undated > 0 {
+		report.WriteString(fmt.Sprintf("\nFinished without a date: %d, set \"finished\" to count them\n", undated))
+	}
+
+	return report.String(), nil
+}
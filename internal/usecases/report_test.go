@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+)
+
+func book(progress, extra string) string {
+	return "---\nprogress: " + progress + "\n" + extra + "---\n"
+}
+
+func TestGetReadingStats(t *testing.T) {
+	us, _ := newTestObsidian(t, map[string]string{
+		"Books/Dune.md":      book("finished", "rating: 5\nfinished: 2026-03-02\npages: 600\n"),
+		"Books/Messiah.md":   book("finished", "rating: 4\nfinished: 2026-03-20\npages: 300\n"),
+		"Books/Old.md":       book("finished", "finished: 2025-12-30\npages: 100\n"),
+		"Books/Undated.md":   book("finished", "started: 2026-01-10\nadded: 2026-01-01\n"),
+		"Books/Reading.md":   book("in_progress", "started: 2026-04-01\n"),
+		"Books/Broken.md":    "---\n: [\n---\n",
+		"Books/NotANote.txt": "",
+	})
+
+	report, err := us.GetReadingStats(testContext(), "/reading_stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"**Reading stats 2026**",
+		"- Jan: 0\n",
+		"- Mar: 2\n",
+		"- May: 0\n",
+		"Finished: 2\n",
+		"Average rating: 4.5 (2 rated)\n",
+		"Pages read: 900\n",
+		"Finished without a date: 1",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report has no %q:\n%s", want, report)
+		}
+	}
+
+	if strings.Contains(report, "- Jun") {
+		t.Errorf("report shows months after the frozen now:\n%s", report)
+	}
+}
+
+func TestGetReadingListFilters(t *testing.T) {
+	us, _ := newTestObsidian(t, map[string]string{
+		"Books/Dune.md":    book("finished", "name: Dune\nrating: 5\nfinished: 2026-03-02\n"),
+		"Books/Old.md":     book("finished", "name: Old\nrating: 3\nfinished: 2025-12-30\n"),
+		"Books/Undated.md": book("finished", "name: Undated\nstarted: 2026-01-10\n"),
+		"Books/Next.md":    book("not_started", "name: Next\nadded: 2026-02-01\n"),
+	})
+
+	tests := []struct {
+		msg     string
+		want    []string
+		notWant []string
+	}{
+		{"/reading_list year:2026", []string{"Dune", "Next"}, []string{"Old", "Undated"}},
+		{"/reading_list finished rating:4", []string{"Dune"}, []string{"Old", "Next", "Not Started"}},
+		{"/reading_list 2025", []string{"Old"}, []string{"Dune", "Undated"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			report, err := us.GetReadingList(testContext(), tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(report, want) {
+					t.Errorf("report has no %q:\n%s", want, report)
+				}
+			}
+
+			for _, notWant := range tt.notWant {
+				if strings.Contains(report, notWant) {
+					t.Errorf("report has %q:\n%s", notWant, report)
+				}
+			}
+		})
+	}
+
+	if _, err := us.GetReadingList(testContext(), "/reading_list bogus"); err == nil {
+		t.Error("unknown filter must fail")
+	}
+}
+
+func TestGetWatchingListDuration(t *testing.T) {
+	us, _ := newTestObsidian(t, map[string]string{
+		"Films/Alien.md": book("finished", "name: Alien\nrating: 4.5\nduration: 1h 57m\nfinished: 2026-04-01\n"),
+	})
+
+	report, err := us.GetWatchingList(testContext(), "/watching_list finished")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "- Alien ⭐ 4.5 ⏱ 1h 57m (finished 2026-04-01)\n"; !strings.Contains(report, want) {
+		t.Errorf("report has no %q:\n%s", want, report)
+	}
+}
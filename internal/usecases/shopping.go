@@ -0,0 +1,326 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"github.com/r-mol/ObsidianBot/pkg/markdown"
+)
+
+// defaultSectionLevel is the heading level of sections created by the bot.
+const defaultSectionLevel = 2
+
+// shoppingEntry links a list item of the note with its view.
+type shoppingEntry struct {
+	node *markdown.Item
+	item models.ShoppingItem
+}
+
+// shoppingEntries flattens the list in document order, numbering items
+// the same way for listing, removing and toggling.
+func shoppingEntries(doc *markdown.Document) []shoppingEntry {
+	var entries []shoppingEntry
+	doc.Walk(func(section *markdown.Section, node *markdown.Item, depth int) {
+		index := len(entries)
+		entries = append(entries, shoppingEntry{
+			node: node,
+			item: models.ShoppingItem{
+				ID:      fmt.Sprintf("%d:%08x", index, crc32.ChecksumIEEE([]byte(node.Text))),
+				Text:    node.Text,
+				Checked: node.Checked,
+				Section: section.Title,
+				Depth:   depth,
+			},
+		})
+	})
+
+	return entries
+}
+
+func (us *obsidian) readShoppingList(ctx context.Context) (*markdown.Document, error) {
+	data, err := us.repo(ctx).ReadFromFile(us.Vault.ShoppingList)
+	if err != nil {
+		return nil, fmt.Errorf("read from file: %w", err)
+	}
+
+	doc, err := markdown.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse shopping list: %w", err)
+	}
+
+	return doc, nil
+}
+
+// updateShoppingList parses the shopping list, applies update to it and
+// writes it back in one locked step.
+func (us *obsidian) updateShoppingList(ctx context.Context, update func(doc *markdown.Document) error) error {
+	return us.updateFile(ctx, us.Vault.ShoppingList, func(data string) (string, error) {
+		doc, err := markdown.Parse(data)
+		if err != nil {
+			return "", fmt.Errorf("parse shopping list: %w", err)
+		}
+
+		if err := update(doc); err != nil {
+			return "", err
+		}
+
+		return doc.String(), nil
+	})
+}
+
+func (us *obsidian) GetShoppingItems(ctx context.Context) ([]models.ShoppingItem, error) {
+	doc, err := us.readShoppingList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := shoppingEntries(doc)
+
+	items := make([]models.ShoppingItem, len(entries))
+	for i, entry := range entries {
+		items[i] = entry.item
+	}
+
+	return items, nil
+}
+
+// GetShoppingList shows unchecked items grouped by section and then the
+// checked ones. Numbers are the ones /remove_item accepts.
+func (us *obsidian) GetShoppingList(ctx context.Context, msg string) (string, error) {
+	items, err := us.GetShoppingItems(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get items: %w", err)
+	}
+
+	if len(items) == 0 {
+		return "Shopping list is empty!", nil
+	}
+
+	var content strings.Builder
+	var section string
+	var checked []string
+	for i, item := range items {
+		indent := strings.Repeat("    ", item.Depth)
+
+		if item.Checked {
+			checked = append(checked, fmt.Sprintf("%s✅ %d. %s\n", indent, i+1, item.Text))
+			continue
+		}
+
+		if item.Section != section {
+			section = item.Section
+			content.WriteString(fmt.Sprintf("\n**%s**\n", section))
+		}
+
+		content.WriteString(fmt.Sprintf("%s%d. %s\n", indent, i+1, item.Text))
+	}
+
+	if len(checked) > 0 {
+		content.WriteString("\n**Checked**\n")
+		content.WriteString(strings.Join(checked, ""))
+	}
+
+	return strings.TrimPrefix(content.String(), "\n"), nil
+}
+
+// AddItemsToShoppingList appends items to the shopping list. Headings in
+// the message choose the section the following items are added to, items
+// without a heading are routed by the keywords of the categories note.
+// Items already waiting in the list are merged by summing quantities.
+func (us *obsidian) AddItemsToShoppingList(ctx context.Context, msg string) (string, error) {
+	lines, err := extractItems(msg)
+	if err != nil {
+		return "", fmt.Errorf("extract new items to slice: %w", err)
+	}
+
+	newDoc, err := markdown.Parse(strings.Join(lines, "\n"))
+	if err != nil {
+		return "", fmt.Errorf("parse new items: %w", err)
+	}
+
+	categories, err := us.readCategories(ctx)
+	if err != nil {
+		return "", fmt.Errorf("read categories: %w", err)
+	}
+
+	var added, merged []string
+	err = us.updateShoppingList(ctx, func(doc *markdown.Document) error {
+		for _, newSection := range newDoc.Sections {
+			for _, item := range newSection.Items {
+				if existing, ok := mergeShoppingItem(doc, item); ok {
+					merged = append(merged, fmt.Sprintf("- %s → %s", item.Text, existing.Text))
+					continue
+				}
+
+				markTask(item)
+				added = append(added, "- "+item.Text)
+
+				title := newSection.Title
+				if title == "" {
+					title, _ = matchCategory(categories, parseQuantity(item.Text).Name)
+				} else if category, ok := findCategory(categories, title); ok {
+					title = category
+				}
+
+				level := newSection.Level
+				if level == 0 && title != "" {
+					level = defaultSectionLevel
+				}
+
+				section := doc.AddSection(title, level)
+				section.Items = append(section.Items, item)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	var reply strings.Builder
+	reply.WriteString("Successfully add items to shopping list.\n")
+	if len(added) > 0 {
+		reply.WriteString(fmt.Sprintf("\nNew:\n%s\n", strings.Join(added, "\n")))
+	}
+	if len(merged) > 0 {
+		reply.WriteString(fmt.Sprintf("\nMerged:\n%s\n", strings.Join(merged, "\n")))
+	}
+	reply.WriteString("\nYou can check it by /shopping_list")
+
+	return reply.String(), nil
+}
+
+// mergeShoppingItem adds the quantity of the item to the same unchecked
+// item of the list and returns the updated item.
+func mergeShoppingItem(doc *markdown.Document, item *markdown.Item) (*markdown.Item, bool) {
+	if item.IsRaw() || item.Checked || len(item.Children) > 0 {
+		return nil, false
+	}
+
+	q := parseQuantity(item.Text)
+
+	var existing *markdown.Item
+	doc.Walk(func(_ *markdown.Section, node *markdown.Item, _ int) {
+		if existing != nil || node.Checked {
+			return
+		}
+
+		if sum, ok := parseQuantity(node.Text).merge(q); ok {
+			node.Text = sum.String()
+			existing = node
+		}
+	})
+
+	return existing, existing != nil
+}
+
+// markTask turns new items into unchecked tasks, keeping explicit checkboxes.
+func markTask(item *markdown.Item) {
+	if item.IsRaw() {
+		return
+	}
+
+	item.Task = true
+	for _, child := range item.Children {
+		markTask(child)
+	}
+}
+
+func (us *obsidian) ClearShoppingList(ctx context.Context, msg string) (string, error) {
+	err := us.updateFile(ctx, us.Vault.ShoppingList, func(string) (string, error) {
+		return "", nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	return "Successfully clear shopping list.", nil
+}
+
+func (us *obsidian) RemoveItemsFromShoppingList(ctx context.Context, msg string) (string, error) {
+	ids, err := parseIDs(msg)
+	if err != nil {
+		return "", err
+	}
+
+	var removedItems []string
+	err = us.updateShoppingList(ctx, func(doc *markdown.Document) error {
+		entries := shoppingEntries(doc)
+
+		for _, id := range ids {
+			if id < 0 || id >= len(entries) {
+				return fmt.Errorf("invalid line index: %d", id+1)
+			}
+		}
+
+		removed := make(map[*markdown.Item]struct{}, len(ids))
+		for _, id := range ids {
+			removed[entries[id].node] = struct{}{}
+			removedItems = append(removedItems, "- "+entries[id].item.Text)
+		}
+
+		doc.Remove(func(item *markdown.Item) bool {
+			_, ok := removed[item]
+			return ok
+		})
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully delete items from shopping list. Items:\n\n%s", strings.Join(removedItems, "\n")), nil
+}
+
+func (us *obsidian) ToggleShoppingItem(ctx context.Context, id string) (string, error) {
+	var toggled *markdown.Item
+	err := us.updateShoppingList(ctx, func(doc *markdown.Document) error {
+		for _, entry := range shoppingEntries(doc) {
+			if entry.item.ID != id {
+				continue
+			}
+
+			entry.node.Task = true
+			entry.node.Checked = !entry.node.Checked
+			toggled = entry.node
+
+			return nil
+		}
+
+		return fmt.Errorf("item not found, shopping list has changed [id = %q]", id)
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	if toggled.Checked {
+		return fmt.Sprintf("Checked %q.", toggled.Text), nil
+	}
+
+	return fmt.Sprintf("Unchecked %q.", toggled.Text), nil
+}
+
+func (us *obsidian) RemoveCheckedShoppingItems(ctx context.Context, msg string) (string, error) {
+	var removed int
+	err := us.updateShoppingList(ctx, func(doc *markdown.Document) error {
+		doc.Remove(func(item *markdown.Item) bool {
+			if item.Checked {
+				removed++
+			}
+
+			return item.Checked
+		})
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	return fmt.Sprintf("Removed %d checked items.", removed), nil
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import "testing"
+
+func TestGetShoppingListSkipsProperties(t *testing.T) {
+	us, _ := newTestObsidian(t, map[string]string{
+		"Shopping List.md": "---\ntags:\n  - shopping\naliases:\n  - groceries\n---\n- [ ] milk\n",
+	})
+
+	got, err := us.GetShoppingList(testContext(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "1. milk\n"; got != want {
+		t.Errorf("GetShoppingList() = %q, want %q", got, want)
+	}
+}
+
+func TestToggleShoppingItem(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Shopping List.md": "- [ ] milk\n- eggs\n",
+	})
+	ctx := testContext()
+
+	items, err := us.GetShoppingItems(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id   string
+		msg  string
+		want string
+	}{
+		{id: items[0].ID, msg: `Checked "milk".`, want: "- [x] milk\n- eggs\n"},
+		{id: items[0].ID, msg: `Unchecked "milk".`, want: "- [ ] milk\n- eggs\n"},
+		// plain items become tasks
+		{id: items[1].ID, msg: `Checked "eggs".`, want: "- [ ] milk\n- [x] eggs\n"},
+	}
+
+	for _, tt := range tests {
+		msg, err := us.ToggleShoppingItem(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if msg != tt.msg {
+			t.Errorf("ToggleShoppingItem() = %q, want %q", msg, tt.msg)
+		}
+
+		if got := readTestFile(t, dir, "Shopping List.md"); got != tt.want {
+			t.Errorf("shopping list = %q, want %q", got, tt.want)
+		}
+	}
+
+	// the keyboard is stale once the list has changed
+	writeTestFile(t, dir, "Shopping List.md", "- [ ] bread\n")
+
+	if _, err := us.ToggleShoppingItem(ctx, items[0].ID); err == nil {
+		t.Error("stale item must fail")
+	}
+
+	if got := readTestFile(t, dir, "Shopping List.md"); got != "- [ ] bread\n" {
+		t.Errorf("shopping list = %q, want it unchanged", got)
+	}
+}
+
+func TestRemoveCheckedShoppingItems(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Shopping List.md": "## Dairy\n- [x] milk\n- [ ] cheese\n\n## Bakery\n- [ ] bread\n    - [x] rye\n",
+	})
+
+	msg, err := us.RemoveCheckedShoppingItems(testContext(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != "Removed 2 checked items." {
+		t.Errorf("RemoveCheckedShoppingItems() = %q", msg)
+	}
+
+	want := "## Dairy\n- [ ] cheese\n\n## Bakery\n- [ ] bread\n"
+	if got := readTestFile(t, dir, "Shopping List.md"); got != want {
+		t.Errorf("shopping list = %q, want %q", got, want)
+	}
+}
+
+func TestAddItemsToShoppingListGroupsByCategory(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Shopping Categories.md": "## Dairy\n- milk\n- cheese\n\n## Bakery\n- bread\n",
+		"Shopping List.md":       "- [ ] soap\n",
+	})
+	ctx := testContext()
+
+	// keywords route items, headings of the message and the argument of the
+	// tag choose the category whatever their case
+	for _, msg := range []string{"#shopping\n2 milks\nbread\nbatteries", "#shopping dairy\nyogurt", "#shopping\n## BAKERY\nbuns"} {
+		if _, err := us.ParseMessage(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "- [ ] soap\n- [ ] batteries\n\n## Dairy\n- [ ] 2 milks\n- [ ] yogurt\n\n## Bakery\n- [ ] bread\n- [ ] buns\n"
+	if got := readTestFile(t, dir, "Shopping List.md"); got != want {
+		t.Errorf("shopping list = %q, want %q", got, want)
+	}
+
+	list, err := us.GetShoppingList(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = "1. soap\n2. batteries\n\n**Dairy**\n3. 2 milks\n4. yogurt\n\n**Bakery**\n5. bread\n6. buns\n"
+	if list != want {
+		t.Errorf("GetShoppingList() = %q, want %q", list, want)
+	}
+}
@@ -1,9 +1,21 @@
 package usecases
 
-const (
-	FilePathInboxTemplate = "Bins/Templates/Inbox.md"
-)
-
 type Inbox struct {
 	Title string
+	// Content is the message without its first line, the title.
+	Content string
+}
+
+type Book struct {
+	Name     string
+	Author   string
+	Progress string
+	Added    string
+}
+
+type Film struct {
+	Name     string
+	Director string
+	Progress string
+	Added    string
 }
@@ -0,0 +1,331 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/r-mol/ObsidianBot/internal/models"
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+	"github.com/r-mol/ObsidianBot/pkg/markdown"
+	"golang.org/x/exp/slices"
+)
+
+// previewLines is the number of body lines shown when sorting the inbox.
+const previewLines = 5
+
+// inboxIDs remembers the paths of inbox notes listed to every user, so
+// keyboard callbacks find the note without walking the vault again.
+type inboxIDs struct {
+	mu    sync.Mutex
+	paths map[int64]map[string]string
+}
+
+func (ids *inboxIDs) add(userID int64, notes ...models.InboxNote) {
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	if ids.paths == nil {
+		ids.paths = make(map[int64]map[string]string)
+	}
+
+	if ids.paths[userID] == nil {
+		ids.paths[userID] = make(map[string]string)
+	}
+
+	for _, note := range notes {
+		ids.paths[userID][note.ID] = note.Path
+	}
+}
+
+// reset forgets the notes listed before, they may be gone since.
+func (ids *inboxIDs) reset(userID int64) {
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	delete(ids.paths, userID)
+}
+
+func (ids *inboxIDs) path(userID int64, id string) (string, bool) {
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	fp, ok := ids.paths[userID][id]
+
+	return fp, ok
+}
+
+func (us *obsidian) GetInboxNotes(ctx context.Context) ([]models.InboxNote, error) {
+	paths, err := us.inboxNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]models.InboxNote, 0, len(paths))
+	for _, fp := range paths {
+		notes = append(notes, newInboxNote(fp))
+	}
+
+	us.inboxIDs.reset(userID(ctx))
+	us.inboxIDs.add(userID(ctx), notes...)
+
+	return notes, nil
+}
+
+func newInboxNote(fp string) models.InboxNote {
+	return models.InboxNote{
+		ID:    fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(fp))),
+		Title: noteTitle(fp),
+		Path:  fp,
+	}
+}
+
+// GetInboxNote returns the inbox note with its preview. The note is looked
+// up by the path it was listed with, the vault is walked only when the list
+// is unknown, e.g. after a restart.
+func (us *obsidian) GetInboxNote(ctx context.Context, id string) (models.InboxNote, error) {
+	errGone := fmt.Errorf("note is not in the inbox anymore, refresh the list")
+
+	fp, ok := us.inboxIDs.path(userID(ctx), id)
+	if !ok {
+		notes, err := us.GetInboxNotes(ctx)
+		if err != nil {
+			return models.InboxNote{}, err
+		}
+
+		i := slices.IndexFunc(notes, func(note models.InboxNote) bool { return note.ID == id })
+		if i < 0 {
+			return models.InboxNote{}, errGone
+		}
+		fp = notes[i].Path
+	}
+
+	exist, err := us.repo(ctx).FileExist(fp)
+	if err != nil {
+		return models.InboxNote{}, fmt.Errorf("check file exist: %w", err)
+	}
+
+	if !exist {
+		return models.InboxNote{}, errGone
+	}
+
+	n, err := us.readNote(ctx, fp)
+	if err != nil {
+		return models.InboxNote{}, err
+	}
+
+	if !isInboxNote(n) {
+		return models.InboxNote{}, errGone
+	}
+
+	note := newInboxNote(fp)
+
+	lines := strings.Split(noteText(n, note.Title), "\n")
+	if len(lines) > previewLines {
+		lines = append(lines[:previewLines], "…")
+	}
+	note.Preview = strings.Join(lines, "\n")
+
+	return note, nil
+}
+
+// TriageOptions returns folders and tags inbox notes can be sorted into.
+func (us *obsidian) TriageOptions() (folders, tags []string) {
+	return us.Vault.TriageFolders, us.Vault.TriageTags
+}
+
+// MoveInboxNote removes the inbox tag from the note and moves it to the
+// triage folder with the given index.
+func (us *obsidian) MoveInboxNote(ctx context.Context, id string, folder int) (string, error) {
+	if folder < 0 || folder >= len(us.Vault.TriageFolders) {
+		return "", fmt.Errorf("unknown folder [index = %d]", folder)
+	}
+
+	note, err := us.GetInboxNote(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	to := filepath.Join(us.Vault.TriageFolders[folder], filepath.Base(note.Path))
+
+	err = us.batch(ctx, func(ctx context.Context) error {
+		err := us.updateNote(ctx, note.Path, func(n *frontmatter.Note) error {
+			return retag(n, "")
+		})
+		if err != nil {
+			return fmt.Errorf("remove inbox tag: %w", err)
+		}
+
+		return us.moveFile(ctx, note.Path, to)
+	})
+	if err != nil {
+		return "", fmt.Errorf("move note: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully move note %q to %q.", note.Title, us.Vault.TriageFolders[folder]), nil
+}
+
+// RetagInboxNote replaces the inbox tag of the note with the triage tag
+// with the given index.
+func (us *obsidian) RetagInboxNote(ctx context.Context, id string, tag int) (string, error) {
+	if tag < 0 || tag >= len(us.Vault.TriageTags) {
+		return "", fmt.Errorf("unknown tag [index = %d]", tag)
+	}
+
+	note, err := us.GetInboxNote(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	err = us.updateNote(ctx, note.Path, func(n *frontmatter.Note) error {
+		return retag(n, us.Vault.TriageTags[tag])
+	})
+	if err != nil {
+		return "", fmt.Errorf("retag note: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully tag note %q with #%s.", note.Title, strings.TrimPrefix(us.Vault.TriageTags[tag], "#")), nil
+}
+
+// InboxNoteToTask adds the note title as a task and moves the note to trash.
+func (us *obsidian) InboxNoteToTask(ctx context.Context, id string) (string, error) {
+	note, err := us.GetInboxNote(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	err = us.batch(ctx, func(ctx context.Context) error {
+		err := us.appendToNote(ctx, us.Vault.TasksList, fmt.Sprintf("\n- [ ] %s", note.Title))
+		if err != nil {
+			return fmt.Errorf("add task: %w", err)
+		}
+
+		return us.moveToTrash(ctx, note.Path)
+	})
+	if err != nil {
+		return "", fmt.Errorf("convert note to task: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully convert note %q to task in %q.", note.Title, us.Vault.TasksList), nil
+}
+
+// InboxNoteToTimestamps appends the note to today's timestamps note and
+// moves the note to trash.
+func (us *obsidian) InboxNoteToTimestamps(ctx context.Context, id string) (string, error) {
+	note, err := us.GetInboxNote(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	n, err := us.readNote(ctx, note.Path)
+	if err != nil {
+		return "", err
+	}
+
+	entry := note.Title
+	if text := noteText(n, note.Title); text != "" {
+		entry += "\n" + text
+	}
+
+	var fp string
+	err = us.batch(ctx, func(ctx context.Context) error {
+		fp, err = us.appendToTimestamps(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		return us.moveToTrash(ctx, note.Path)
+	})
+	if err != nil {
+		return "", fmt.Errorf("append note to timestamps: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully append note %q to %s.", note.Title, fp), nil
+}
+
+// DeleteInboxNote moves the note to the vault trash folder.
+func (us *obsidian) DeleteInboxNote(ctx context.Context, id string) (string, error) {
+	note, err := us.GetInboxNote(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	err = us.batch(ctx, func(ctx context.Context) error {
+		return us.moveToTrash(ctx, note.Path)
+	})
+	if err != nil {
+		return "", fmt.Errorf("delete note: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully delete note %q.", note.Title), nil
+}
+
+// moveToTrash moves the note to the trash folder, a timestamp is added to
+// the name if the trash already has such note.
+func (us *obsidian) moveToTrash(ctx context.Context, fp string) error {
+	to := filepath.Join(us.Vault.TrashDir, filepath.Base(fp))
+
+	exist, err := us.repo(ctx).FileExist(to)
+	if err != nil {
+		return fmt.Errorf("check file exist: %w", err)
+	}
+
+	if exist {
+		to = filepath.Join(us.Vault.TrashDir, fmt.Sprintf("%s %s.md", noteTitle(fp), us.Clock.Now().Format("2006-01-02 150405")))
+	}
+
+	return us.moveFile(ctx, fp, to)
+}
+
+// retag removes the inbox tag and its nested tags from the properties and
+// body of the note and adds the tag to the properties, if it is not empty.
+func retag(note *frontmatter.Note, tag string) error {
+	hadTags := note.Has("tags") || note.Has("tag")
+
+	var tags []string
+	for _, t := range note.Tags() {
+		if !markdown.HasTag([]string{t}, string(TagInbox)) && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+
+	tag = strings.TrimPrefix(tag, "#")
+	if tag != "" && !markdown.HasTag(tags, tag) {
+		tags = append(tags, tag)
+	}
+
+	note.Body = markdown.RemoveTag(note.Body, string(TagInbox))
+	note.Delete("tag")
+
+	if len(tags) == 0 {
+		note.Delete("tags")
+		return nil
+	}
+
+	if !hadTags && !note.HasProperties() {
+		// keep notes without properties plain when only the body had tags
+		note.Body = strings.TrimRight(note.Body, "\n") + fmt.Sprintf("\n#%s\n", tag)
+		return nil
+	}
+
+	return note.Set("tags", tags)
+}
+
+func noteTitle(fp string) string {
+	return strings.TrimSuffix(filepath.Base(fp), ".md")
+}
+
+// noteText returns the body of the note without inbox tags and the title
+// heading added by the inbox template.
+func noteText(note *frontmatter.Note, title string) string {
+	text := strings.TrimSpace(markdown.RemoveTag(note.Body, string(TagInbox)))
+
+	if first, rest, _ := strings.Cut(text, "\n"); strings.EqualFold(strings.TrimSpace(strings.TrimLeft(first, "#")), title) {
+		text = strings.TrimSpace(rest)
+	}
+
+	return text
+}
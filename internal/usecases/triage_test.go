@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetInboxNote(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Idea.md":         "---\ntags: [inbox]\n---\n# Idea\n\nwrite a bot\n",
+		"Projects/Bot.md": "#inbox/work\nship it\n",
+		"Done.md":         "---\ntags: [done]\n---\n",
+	})
+	ctx := testContext()
+
+	notes, err := us.GetInboxNotes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notes) != 2 {
+		t.Fatalf("GetInboxNotes() = %v, want 2 notes", notes)
+	}
+
+	note, err := us.GetInboxNote(ctx, notes[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if note.Path != "Idea.md" || note.Preview != "write a bot" {
+		t.Errorf("GetInboxNote() = %+v, want Idea.md with preview", note)
+	}
+
+	// the note was sorted outside of the bot
+	writeTestFile(t, dir, "Idea.md", "---\ntags: [done]\n---\n")
+	if _, err := us.GetInboxNote(ctx, notes[0].ID); err == nil {
+		t.Error("GetInboxNote() of a sorted note succeeded")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "Projects/Bot.md")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.GetInboxNote(ctx, notes[1].ID); err == nil {
+		t.Error("GetInboxNote() of a removed note succeeded")
+	}
+}
+
+func TestGetInboxNoteWithoutList(t *testing.T) {
+	us, _ := newTestObsidian(t, map[string]string{
+		"Idea.md": "---\ntags: [inbox]\n---\n# Idea\n",
+	})
+
+	// an id from a keyboard shown before a restart
+	note, err := us.GetInboxNote(testContext(), newInboxNote("Idea.md").ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if note.Path != "Idea.md" {
+		t.Errorf("GetInboxNote() = %+v, want Idea.md", note)
+	}
+}
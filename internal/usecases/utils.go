@@ -4,30 +4,34 @@ import (
 	"bufio"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-func extractTagAndText(message string) (string, string, error) {
-	re := regexp.MustCompile(`(?m)^#\s*(\w+)\s*\n([\s\S]*)`)
+var (
+	listItemRe = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+\S`)
+	headingRe  = regexp.MustCompile(`^#{1,6}\s+\S`)
+	// "{{title}}" placeholders of templates
+	placeholderRe = regexp.MustCompile(`{{\s*([a-zA-Z0-9_]+)\s*}}`)
+)
+
+// extractTagAndText splits "#tag argument\ntext" message into its parts,
+// the argument and the text are optional. The tag must open the message,
+// a space after "#" is allowed: "# shopping".
+func extractTagAndText(message string) (string, string, string, error) {
+	re := regexp.MustCompile(`^#[ \t]*(\w+)[ \t]*([^\n]*?)[ \t]*(?:\n([\s\S]*))?$`)
 
 	match := re.FindStringSubmatch(message)
 
-	if len(match) > 2 {
-		return match[1], match[2], nil
+	if len(match) > 3 {
+		return match[1], match[2], match[3], nil
 	}
 
-	return "", "", fmt.Errorf("no tag found in the message")
-}
-
-func isSingleLine(input string) bool {
-	re := regexp.MustCompile(`^[^\n\r]*$`)
-	return re.MatchString(input)
+	return "", "", "", fmt.Errorf("no tag found in the message")
 }
 
 func transformPlaceholders(input string) string {
-	// Regular expression to match {{key}} placeholders
-	re := regexp.MustCompile(`{{\s*([a-zA-Z0-9_]+)\s*}}`)
-	return re.ReplaceAllStringFunc(input, func(match string) string {
+	return placeholderRe.ReplaceAllStringFunc(input, func(match string) string {
 		// Extract the placeholder key
 		key := strings.TrimSpace(match[2 : len(match)-2])
 		// Convert to {{.Key}} format
@@ -35,6 +39,8 @@ func transformPlaceholders(input string) string {
 	})
 }
 
+// extractItems turns plain lines of the text into list items, headings and
+// list items (with their indentation and checkboxes) are kept as is.
 func extractItems(text string) ([]string, error) {
 	var result []string
 
@@ -46,25 +52,18 @@ func extractItems(text string) ([]string, error) {
 		// Trim leading and trailing whitespace
 		trimmedLine := strings.TrimSpace(line)
 
-		// Skip empty lines
-		if len(trimmedLine) == 0 {
+		// Skip empty lines and lonely markers
+		if len(trimmedLine) == 0 || trimmedLine == "-" {
 			continue
 		}
 
 		// Apply rules to process the line
-		if strings.HasPrefix(trimmedLine, "- ") {
-			if len(trimmedLine) == 2 {
-				continue
-			}
-
-			result = append(result, trimmedLine)
-		} else if strings.HasPrefix(trimmedLine, "-") {
-			if len(trimmedLine) == 1 {
-				continue
-			}
-
+		switch {
+		case listItemRe.MatchString(line), headingRe.MatchString(trimmedLine):
+			result = append(result, strings.TrimRight(line, " \t"))
+		case strings.HasPrefix(trimmedLine, "-"):
 			result = append(result, "- "+strings.TrimSpace(trimmedLine[1:]))
-		} else {
+		default:
 			result = append(result, "- "+trimmedLine)
 		}
 	}
@@ -76,3 +75,57 @@ func extractItems(text string) ([]string, error) {
 
 	return result, nil
 }
+
+// forbiddenTitleChars are characters Obsidian does not allow in note names,
+// together with wikilink brackets.
+var forbiddenTitleChars = strings.NewReplacer(
+	"[[", "", "]]", "",
+	`\`, "", "/", "", ":", "", "*", "", "?", "", `"`, "", "<", "", ">", "", "|", "",
+)
+
+func sanitizeTitle(title string) string {
+	title = forbiddenTitleChars.Replace(title)
+	title = strings.Join(strings.Fields(title), " ")
+
+	return strings.TrimLeft(title, ". ")
+}
+
+// trimCommand removes the leading "/command" from the message.
+func trimCommand(msg string) string {
+	msg = strings.TrimSpace(msg)
+	if !strings.HasPrefix(msg, "/") {
+		return msg
+	}
+
+	i := strings.IndexAny(msg, " \t\n")
+	if i < 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(msg[i+1:])
+}
+
+// parseIDs parses comma separated one-based item numbers of the command
+// into zero-based indexes.
+func parseIDs(msg string) ([]int, error) {
+	msg = trimCommand(msg)
+	if msg == "" {
+		return nil, fmt.Errorf("should be provided minimum one id")
+	}
+
+	args := strings.Split(msg, ",")
+
+	var ids = make([]int, len(args))
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("convert string to int [id = %q]: %w", arg, err)
+		}
+
+		ids[i] = id - 1
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import "testing"
+
+func TestExtractTagAndText(t *testing.T) {
+	tests := []struct {
+		msg            string
+		tag, arg, text string
+		wantErr        bool
+	}{
+		{msg: "#shopping\nmilk\nbread", tag: "shopping", text: "milk\nbread"},
+		{msg: "# shopping\nmilk", tag: "shopping", text: "milk"},
+		{msg: "#shopping dairy\nmilk", tag: "shopping", arg: "dairy", text: "milk"},
+		{msg: "#action went for a run  ", tag: "action", arg: "went for a run"},
+		{msg: "#book Dune\nFrank Herbert", tag: "book", arg: "Dune", text: "Frank Herbert"},
+		{msg: "just text", wantErr: true},
+		{msg: "text\n#shopping\nmilk", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			tag, arg, text, err := extractTagAndText(tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractTagAndText(%q) error = %v, wantErr %v", tt.msg, err, tt.wantErr)
+			}
+
+			if tag != tt.tag || arg != tt.arg || text != tt.text {
+				t.Errorf("extractTagAndText(%q) = %q, %q, %q, want %q, %q, %q", tt.msg, tag, arg, text, tt.tag, tt.arg, tt.text)
+			}
+		})
+	}
+}
+
+func TestSanitizeTitle(t *testing.T) {
+	tests := map[string]string{
+		"a/b: c?":         "ab c",
+		"[[Link]] | note": "Link note",
+		"..hidden":        "hidden",
+	}
+
+	for title, want := range tests {
+		if got := sanitizeTitle(title); got != want {
+			t.Errorf("sanitizeTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
+)
+
+// CheckVaults verifies that every configured path exists in every vault.
+func (us *obsidian) CheckVaults() error {
+	repos := append([]Repository{us.Repo}, maps.Values(us.UserRepos)...)
+
+	files := []string{us.Vault.ShoppingList, us.Vault.WishList, us.Vault.InboxTemplate}
+	dirs := []string{us.Vault.TimestampsDir, us.Vault.BooksDir, us.Vault.FilmsDir}
+
+	for _, repo := range repos {
+		for _, fp := range files {
+			exist, err := repo.FileExist(fp)
+			if err != nil {
+				return fmt.Errorf("check file exist: %w", err)
+			}
+
+			if !exist {
+				return xerrors.Errorf("file %q does not exist in vault", fp)
+			}
+		}
+
+		for _, path := range dirs {
+			exist, err := repo.DirExist(path)
+			if err != nil {
+				return fmt.Errorf("check dir exist: %w", err)
+			}
+
+			if !exist {
+				return xerrors.Errorf("directory %q does not exist in vault", path)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SaveVoice saves the voice message into the attachments folder and creates
+// an inbox note embedding it, with the transcript when it is available.
+// The caption, if any, is the note title.
+func (us *obsidian) SaveVoice(ctx context.Context, file io.Reader, caption string) (string, error) {
+	data, err := readAttachment(file)
+	if err != nil {
+		return "", err
+	}
+
+	title := fmt.Sprintf("Voice %s", us.Clock.Now().Format("2006-01-02 150405"))
+
+	fp, err := us.writeAttachment(ctx, title+".ogg", data)
+	if err != nil {
+		return "", fmt.Errorf("write voice: %w", err)
+	}
+
+	body := fmt.Sprintf("![[%s]]", filepath.ToSlash(fp))
+
+	// the note is worth keeping without the transcript
+	transcript, err := us.Transcriber.Transcribe(ctx, bytes.NewReader(data))
+	if err != nil {
+		log.Warnf("transcribe voice [filepath = %q]: %v", fp, err)
+	}
+
+	if transcript != "" {
+		body += "\n\n" + transcript
+	}
+
+	if caption = strings.TrimSpace(caption); caption != "" {
+		title = caption
+	}
+
+	return us.createAttachmentNote(ctx, fp, title, body)
+}
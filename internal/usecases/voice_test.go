@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type stubTranscriber struct {
+	text string
+}
+
+func (s stubTranscriber) Transcribe(context.Context, io.Reader) (string, error) {
+	return s.text, nil
+}
+
+func TestSaveVoice(t *testing.T) {
+	us, dir := newTestObsidian(t, nil)
+	us.Transcriber = stubTranscriber{text: "buy milk"}
+
+	if _, err := us.SaveVoice(testContext(), strings.NewReader("ogg"), "Errands"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readTestFile(t, dir, "Errands.md")
+	if !strings.Contains(got, "![[Attachments/Voice 2026-05-03 100000.ogg]]\n\nbuy milk") {
+		t.Errorf("note = %q, want the voice embedded with the transcript", got)
+	}
+}
+
+func TestSaveVoiceToExistingNote(t *testing.T) {
+	us, dir := newTestObsidian(t, map[string]string{
+		"Errands.md": "# Errands\n",
+	})
+	us.Transcriber = stubTranscriber{}
+
+	msg, err := us.SaveVoice(testContext(), strings.NewReader("ogg"), "Errands")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg != "Note with such name already exist." {
+		t.Errorf("SaveVoice() = %q", msg)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "Attachments/Voice 2026-05-03 100000.ogg")); !os.IsNotExist(err) {
+		t.Errorf("voice is left without a note: %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/r-mol/ObsidianBot/pkg/markdown"
+)
+
+const (
+	wishFieldLink     = "link"
+	wishFieldPrice    = "price"
+	wishFieldPriority = "priority"
+)
+
+var (
+	inlineFieldRe = regexp.MustCompile(`\s*\[(\w+)::\s*([^\]]*?)\s*\]`)
+	urlRe         = regexp.MustCompile(`^https?://\S+$`)
+	priceRe       = regexp.MustCompile(`^(?:[$€£₽]\d+(?:[.,]\d+)?|\d+(?:[.,]\d+)?[$€£₽])$`)
+	priorityRe    = regexp.MustCompile(`(?i)^!(high|medium|low|[123])$`)
+	numberRe      = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+)
+
+var wishPriorities = map[string]int{
+	"high":   1,
+	"medium": 2,
+	"low":    3,
+}
+
+var wishPriorityIcons = map[string]string{
+	"high":   "🔴",
+	"medium": "🟡",
+	"low":    "🟢",
+}
+
+// wish is an entry of the wish list stored as a list item with inline
+// fields: "- Headphones [link:: https://…] [price:: 150$] [priority:: high]".
+type wish struct {
+	Title    string
+	Link     string
+	Price    string
+	Priority string
+}
+
+// parseWish reads a list item of the wish list.
+func parseWish(text string) wish {
+	var w wish
+	for _, match := range inlineFieldRe.FindAllStringSubmatch(text, -1) {
+		switch strings.ToLower(match[1]) {
+		case wishFieldLink:
+			w.Link = match[2]
+		case wishFieldPrice:
+			w.Price = match[2]
+		case wishFieldPriority:
+			w.Priority = strings.ToLower(match[2])
+		}
+	}
+
+	w.Title = strings.TrimSpace(inlineFieldRe.ReplaceAllString(text, ""))
+
+	return w
+}
+
+// parseWishInput reads a wish typed in Telegram: words are the title, and
+// a URL, a price with currency ("150$", "€20") and a priority ("!high",
+// "!2") may appear anywhere in the line. Inline fields are accepted too.
+func parseWishInput(line string) wish {
+	w := parseWish(line)
+
+	var title []string
+	for _, word := range strings.Fields(w.Title) {
+		switch {
+		case w.Link == "" && urlRe.MatchString(word):
+			w.Link = word
+		case w.Price == "" && priceRe.MatchString(word):
+			w.Price = word
+		case w.Priority == "" && priorityRe.MatchString(word):
+			w.Priority = normalizePriority(priorityRe.FindStringSubmatch(word)[1])
+		default:
+			title = append(title, word)
+		}
+	}
+
+	w.Title = strings.Join(title, " ")
+
+	return w
+}
+
+func normalizePriority(priority string) string {
+	switch strings.ToLower(priority) {
+	case "1":
+		return "high"
+	case "2":
+		return "medium"
+	case "3":
+		return "low"
+	}
+
+	return strings.ToLower(priority)
+}
+
+func (w wish) String() string {
+	fields := []string{w.Title}
+	if w.Link != "" {
+		fields = append(fields, fmt.Sprintf("[%s:: %s]", wishFieldLink, w.Link))
+	}
+	if w.Price != "" {
+		fields = append(fields, fmt.Sprintf("[%s:: %s]", wishFieldPrice, w.Price))
+	}
+	if w.Priority != "" {
+		fields = append(fields, fmt.Sprintf("[%s:: %s]", wishFieldPriority, w.Priority))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// priceValue returns the number of the price, wishes without a price go last.
+func (w wish) priceValue() float64 {
+	value, err := strconv.ParseFloat(strings.Replace(numberRe.FindString(w.Price), ",", ".", 1), 64)
+	if err != nil {
+		return math.Inf(1)
+	}
+
+	return value
+}
+
+// priorityValue orders wishes from high to low, without a priority last.
+func (w wish) priorityValue() int {
+	if value, ok := wishPriorities[w.Priority]; ok {
+		return value
+	}
+
+	return len(wishPriorities) + 1
+}
+
+// wishEntries returns wishes in document order with their list items.
+func wishEntries(doc *markdown.Document) ([]wish, []*markdown.Item) {
+	var wishes []wish
+	var nodes []*markdown.Item
+	doc.Walk(func(_ *markdown.Section, node *markdown.Item, depth int) {
+		if depth > 0 {
+			return
+		}
+
+		wishes = append(wishes, parseWish(node.Text))
+		nodes = append(nodes, node)
+	})
+
+	return wishes, nodes
+}
+
+// GetWishList shows the wish list, "/wish_list price" and
+// "/wish_list priority" sort it. Numbers are the ones /wish_remove accepts.
+func (us *obsidian) GetWishList(ctx context.Context, msg string) (string, error) {
+	data, err := us.repo(ctx).ReadFromFile(us.Vault.WishList)
+	if err != nil {
+		return "", fmt.Errorf("read from file: %w", err)
+	}
+
+	doc, err := markdown.Parse(data)
+	if err != nil {
+		return "", fmt.Errorf("parse wish list: %w", err)
+	}
+
+	wishes, _ := wishEntries(doc)
+	if len(wishes) == 0 {
+		return "Wish list is empty!", nil
+	}
+
+	order := make([]int, len(wishes))
+	for i := range order {
+		order[i] = i
+	}
+
+	switch sortBy := strings.ToLower(trimCommand(msg)); sortBy {
+	case "":
+	case wishFieldPrice:
+		sort.SliceStable(order, func(i, j int) bool {
+			return wishes[order[i]].priceValue() < wishes[order[j]].priceValue()
+		})
+	case wishFieldPriority:
+		sort.SliceStable(order, func(i, j int) bool {
+			return wishes[order[i]].priorityValue() < wishes[order[j]].priorityValue()
+		})
+	default:
+		return "", fmt.Errorf("unknown sort order %q, expected %q or %q", sortBy, wishFieldPrice, wishFieldPriority)
+	}
+
+	var content strings.Builder
+	for _, i := range order {
+		w := wishes[i]
+
+		title := w.Title
+		if w.Link != "" {
+			title = fmt.Sprintf("[%s](%s)", w.Title, w.Link)
+		}
+
+		content.WriteString(fmt.Sprintf("%d. %s", i+1, title))
+		if w.Price != "" {
+			content.WriteString(" — " + w.Price)
+		}
+		if icon, ok := wishPriorityIcons[w.Priority]; ok {
+			content.WriteString(" " + icon)
+		}
+		content.WriteString("\n")
+	}
+
+	return content.String(), nil
+}
+
+// AddItemsToWishList adds a wish per line of the message.
+func (us *obsidian) AddItemsToWishList(ctx context.Context, msg string) (string, error) {
+	var added []string
+	var items []*markdown.Item
+	for _, line := range strings.Split(trimCommand(msg), "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*+"))
+		if line == "" {
+			continue
+		}
+
+		w := parseWishInput(line)
+		if w.Title == "" {
+			return "", fmt.Errorf("wish has no title [line = %q]", line)
+		}
+
+		items = append(items, &markdown.Item{Marker: "-", Text: w.String()})
+		added = append(added, "- "+w.Title)
+	}
+
+	if len(items) == 0 {
+		return "", fmt.Errorf("should be provided minimum one wish")
+	}
+
+	err := us.updateFile(ctx, us.Vault.WishList, func(data string) (string, error) {
+		doc, err := markdown.Parse(data)
+		if err != nil {
+			return "", fmt.Errorf("parse wish list: %w", err)
+		}
+
+		section := doc.Sections[len(doc.Sections)-1]
+		section.Items = append(section.Items, items...)
+
+		return doc.String(), nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully add items to wish list. Items:\n\n%s\n\nYou can check it by /wish_list", strings.Join(added, "\n")), nil
+}
+
+func (us *obsidian) RemoveItemsFromWishList(ctx context.Context, msg string) (string, error) {
+	ids, err := parseIDs(msg)
+	if err != nil {
+		return "", err
+	}
+
+	var removedItems []string
+	err = us.updateFile(ctx, us.Vault.WishList, func(data string) (string, error) {
+		doc, err := markdown.Parse(data)
+		if err != nil {
+			return "", fmt.Errorf("parse wish list: %w", err)
+		}
+
+		wishes, nodes := wishEntries(doc)
+
+		for _, id := range ids {
+			if id < 0 || id >= len(nodes) {
+				return "", fmt.Errorf("invalid line index: %d", id+1)
+			}
+		}
+
+		removed := make(map[*markdown.Item]struct{}, len(ids))
+		for _, id := range ids {
+			removed[nodes[id]] = struct{}{}
+			removedItems = append(removedItems, "- "+wishes[id].Title)
+		}
+
+		doc.Remove(func(item *markdown.Item) bool {
+			_, ok := removed[item]
+			return ok
+		})
+
+		return doc.String(), nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("update file: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully delete items from wish list. Items:\n\n%s", strings.Join(removedItems, "\n")), nil
+}
@@ -0,0 +1,237 @@
+// Package frontmatter reads and edits YAML properties of Obsidian notes.
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+// Note is a note split into its properties and body. Properties are kept
+// as a YAML node, so unknown keys, their order and comments survive edits.
+type Note struct {
+	properties *yaml.Node
+	Body       string
+}
+
+// Parse splits the note into properties and body. A note without the
+// leading "---" block has no properties.
+func Parse(data string) (*Note, error) {
+	data = strings.TrimPrefix(data, "\ufeff")
+
+	block, body, ok := Split(data)
+	if !ok {
+		return &Note{Body: data}, nil
+	}
+
+	return newNote(block, body)
+}
+
+// Split cuts the properties block without its delimiters off the note, the
+// block is not parsed. It reports false when the note has no properties.
+func Split(data string) (block, body string, ok bool) {
+	rest, ok := cutDelimiterLine(strings.TrimPrefix(data, "\ufeff"))
+	if !ok {
+		return "", data, false
+	}
+
+	var lines strings.Builder
+	for {
+		line, tail, found := strings.Cut(rest, "\n")
+		if trimmed := strings.TrimRight(line, " \t\r"); trimmed == delimiter || trimmed == "..." {
+			if !found {
+				tail = ""
+			}
+
+			return lines.String(), tail, true
+		}
+
+		if !found {
+			// no closing delimiter, the dashes are a horizontal rule
+			return "", data, false
+		}
+
+		lines.WriteString(line + "\n")
+		rest = tail
+	}
+}
+
+func cutDelimiterLine(data string) (string, bool) {
+	line, rest, found := strings.Cut(data, "\n")
+	if !found || strings.TrimRight(line, " \t\r") != delimiter {
+		return "", false
+	}
+
+	return rest, true
+}
+
+func newNote(block, body string) (*Note, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(block), &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal properties: %w", err)
+	}
+
+	note := &Note{Body: body}
+
+	switch {
+	case len(doc.Content) == 0:
+		note.properties = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	case doc.Content[0].Kind == yaml.MappingNode:
+		note.properties = doc.Content[0]
+	default:
+		return nil, fmt.Errorf("properties must be a mapping, got %s", doc.Content[0].Tag)
+	}
+
+	return note, nil
+}
+
+// HasProperties reports whether the note has the properties block.
+func (n *Note) HasProperties() bool {
+	return n.properties != nil
+}
+
+func (n *Note) value(key string) *yaml.Node {
+	if n.properties == nil {
+		return nil
+	}
+
+	for i := 0; i+1 < len(n.properties.Content); i += 2 {
+		if n.properties.Content[i].Value == key {
+			return n.properties.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// Has reports whether the property is set.
+func (n *Note) Has(key string) bool {
+	return n.value(key) != nil
+}
+
+// String returns the scalar property, empty for missing and non-scalar ones.
+func (n *Note) String(key string) string {
+	node := n.value(key)
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+
+	return node.Value
+}
+
+// Strings returns the list property, a scalar property is a list of one.
+func (n *Note) Strings(key string) []string {
+	node := n.value(key)
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || node.Value == "" {
+			return nil
+		}
+
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode && item.Tag != "!!null" {
+				values = append(values, item.Value)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+// Tags returns the tags property. Obsidian also accepts the "tag" alias
+// and tags separated by commas or spaces in a single string.
+func (n *Note) Tags() []string {
+	var tags []string
+	for _, key := range []string{"tags", "tag"} {
+		for _, value := range n.Strings(key) {
+			for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+				tags = append(tags, strings.TrimPrefix(tag, "#"))
+			}
+		}
+	}
+
+	return tags
+}
+
+// Set replaces the value of the property in place or appends the property
+// when it is missing.
+func (n *Note) Set(key string, value any) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("encode property %q: %w", key, err)
+	}
+
+	if n.properties == nil {
+		n.properties = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	if current := n.value(key); current != nil {
+		// keep comments attached to the old value
+		node.HeadComment, node.LineComment, node.FootComment = current.HeadComment, current.LineComment, current.FootComment
+		*current = node
+		return nil
+	}
+
+	n.properties.Content = append(n.properties.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&node,
+	)
+
+	return nil
+}
+
+// Delete removes the property.
+func (n *Note) Delete(key string) {
+	if n.properties == nil {
+		return
+	}
+
+	for i := 0; i+1 < len(n.properties.Content); i += 2 {
+		if n.properties.Content[i].Value == key {
+			n.properties.Content = append(n.properties.Content[:i], n.properties.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// Render joins the properties and the body back into the note.
+func (n *Note) Render() (string, error) {
+	if n.properties == nil {
+		return n.Body, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+
+	if len(n.properties.Content) > 0 {
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(n.properties); err != nil {
+			return "", fmt.Errorf("encode properties: %w", err)
+		}
+
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("close encoder: %w", err)
+		}
+	}
+
+	buf.WriteString(delimiter + "\n")
+	buf.WriteString(n.Body)
+
+	return buf.String(), nil
+}
@@ -0,0 +1,97 @@
+package frontmatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRender(t *testing.T) {
+	tests := map[string]string{
+		"plain":    "# Title\n\ntext\n",
+		"empty":    "---\n---\nbody\n",
+		"comments": "---\n# keep me\ntags: [inbox] # and me\nname: Dune\n---\nbody\n",
+		"rule":     "---\nnot properties\n",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			note, err := Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := note.Render()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != data {
+				t.Errorf("Render() = %q, want %q", got, data)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	note, err := Parse("---\nname: old # title\nrating:\n---\nbody\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// missing properties are appended in the order they are set
+	for _, property := range []struct {
+		key   string
+		value any
+	}{
+		{key: "name", value: "Dune: Messiah"},
+		{key: "progress", value: "finished"},
+		{key: "location", value: []float64{1.5, 2}},
+	} {
+		if err := note.Set(property.key, property.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := note.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "---\nname: 'Dune: Messiah' # title\nrating:\nprogress: finished\nlocation:\n  - 1.5\n  - 2\n---\nbody\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	parsed, err := Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.String("name") != "Dune: Messiah" {
+		t.Errorf("String(name) = %q", parsed.String("name"))
+	}
+}
+
+func TestDelete(t *testing.T) {
+	note, err := Parse("---\ntag: inbox\ntags: [a, b]\n---\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note.Delete("tag")
+
+	if got := note.Tags(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Tags() = %q, want [a b]", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	block, body, ok := Split("---\nname: {{name}}\n---\n# {{name}}\n")
+	if !ok || block != "name: {{name}}\n" || body != "# {{name}}\n" {
+		t.Errorf("Split() = %q, %q, %v", block, body, ok)
+	}
+
+	if _, body, ok := Split("text\n---\n"); ok || body != "text\n---\n" {
+		t.Errorf("Split() of a note without properties = %q, %v", body, ok)
+	}
+}
@@ -0,0 +1,283 @@
+// Package markdown models Obsidian notes made of headings and (task) lists.
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/r-mol/ObsidianBot/pkg/frontmatter"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*$`)
+	itemRe    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.*)$`)
+)
+
+// Document is a note split into sections by headings. The first section
+// has no heading and holds the content before the first heading.
+type Document struct {
+	Sections []*Section
+
+	// front is the properties block of the note, it is kept as is.
+	front string
+}
+
+type Section struct {
+	// Level is the heading level, zero for the section before the first heading.
+	Level int
+	Title string
+	Items []*Item
+
+	// heading is the parsed heading line, rendered as is while the level
+	// and title are unchanged.
+	heading string
+	// blank holds the blank lines after the last item, so items appended
+	// to the section go before them.
+	blank []*Item
+}
+
+// Item is a list item with its nested items. Lines which are not list items,
+// blank lines and lines of code blocks are kept as items with Raw or Blank
+// set, so they survive rendering unchanged.
+type Item struct {
+	Indent   string
+	Marker   string
+	Task     bool
+	Checked  bool
+	Text     string
+	Raw      string
+	Blank    bool
+	Children []*Item
+}
+
+// IsRaw reports whether the item is a kept line rather than a list item.
+func (i *Item) IsRaw() bool {
+	return i.Raw != "" || i.Blank
+}
+
+// Parse reads the note, its properties block is not parsed and is
+// rendered back unchanged.
+func Parse(text string) (*Document, error) {
+	doc := &Document{Sections: []*Section{{}}}
+	if _, body, ok := frontmatter.Split(text); ok {
+		doc.front, text = text[:len(text)-len(body)], body
+	}
+	section := doc.Sections[0]
+
+	// stack of items the next line may be nested into
+	var parents []*Item
+	// blank lines which are not followed by a line of the section yet
+	var blank []*Item
+	// the closing marker of the code block the line is in
+	var fence string
+
+	add := func(item *Item) {
+		section.Items = append(section.Items, blank...)
+		section.Items = append(section.Items, item)
+		blank = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			add(&Item{Raw: line, Blank: trimmed == ""})
+			continue
+		}
+
+		if trimmed == "" {
+			blank = append(blank, &Item{Raw: line, Blank: true})
+			parents = nil
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			add(&Item{Raw: line})
+			parents = nil
+			continue
+		}
+
+		if match := headingRe.FindStringSubmatch(line); match != nil {
+			section.blank, blank = blank, nil
+			section = &Section{Level: len(match[1]), Title: match[2], heading: line}
+			doc.Sections = append(doc.Sections, section)
+			parents = nil
+			continue
+		}
+
+		match := itemRe.FindStringSubmatch(line)
+		if match == nil {
+			add(&Item{Raw: line})
+			parents = nil
+			continue
+		}
+
+		item := &Item{
+			Indent:  match[1],
+			Marker:  match[2],
+			Task:    match[3] != "",
+			Checked: strings.EqualFold(match[3], "x"),
+			Text:    match[4],
+		}
+
+		for len(parents) > 0 && indentWidth(parents[len(parents)-1].Indent) >= indentWidth(item.Indent) {
+			parents = parents[:len(parents)-1]
+		}
+
+		if len(parents) == 0 {
+			add(item)
+		} else {
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, item)
+		}
+
+		parents = append(parents, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan lines: %w", err)
+	}
+
+	section.blank = blank
+
+	return doc, nil
+}
+
+func indentWidth(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "    "))
+}
+
+// Section returns the section with the title, matched case-insensitively.
+// Empty title returns the section before the first heading.
+func (d *Document) Section(title string) *Section {
+	for _, section := range d.Sections {
+		if strings.EqualFold(section.Title, title) {
+			return section
+		}
+	}
+
+	return nil
+}
+
+// AddSection returns the section with the title, appending a new section
+// with the heading level when there is no such section.
+func (d *Document) AddSection(title string, level int) *Section {
+	if section := d.Section(title); section != nil {
+		return section
+	}
+
+	section := &Section{Level: level, Title: title}
+	d.Sections = append(d.Sections, section)
+
+	return section
+}
+
+// Walk calls fn for every list item in document order, parents before
+// their children. Raw lines are skipped.
+func (d *Document) Walk(fn func(section *Section, item *Item, depth int)) {
+	var walk func(section *Section, items []*Item, depth int)
+	walk = func(section *Section, items []*Item, depth int) {
+		for _, item := range items {
+			if item.IsRaw() {
+				continue
+			}
+
+			fn(section, item, depth)
+			walk(section, item.Children, depth+1)
+		}
+	}
+
+	for _, section := range d.Sections {
+		walk(section, section.Items, 0)
+	}
+}
+
+// Remove deletes items for which fn returns true together with their children.
+func (d *Document) Remove(fn func(item *Item) bool) {
+	var remove func(items []*Item) []*Item
+	remove = func(items []*Item) []*Item {
+		kept := items[:0]
+		for _, item := range items {
+			if !item.IsRaw() && fn(item) {
+				continue
+			}
+
+			item.Children = remove(item.Children)
+			kept = append(kept, item)
+		}
+
+		return kept
+	}
+
+	for _, section := range d.Sections {
+		section.Items = remove(section.Items)
+	}
+}
+
+// String renders the document, a parsed document is rendered back as it
+// was, only its changes differ. Sections added after parsing are separated
+// from the previous content by a blank line.
+func (d *Document) String() string {
+	var b strings.Builder
+	b.WriteString(d.front)
+
+	for _, section := range d.Sections {
+		switch {
+		case section.Level == 0:
+		case section.parsedHeading():
+			b.WriteString(section.heading + "\n")
+		default:
+			if section.heading == "" && b.Len() > len(d.front) && !strings.HasSuffix(b.String(), "\n\n") {
+				b.WriteString("\n")
+			}
+
+			b.WriteString(strings.Repeat("#", section.Level) + " " + section.Title + "\n")
+		}
+
+		for _, item := range section.Items {
+			item.write(&b)
+		}
+
+		for _, item := range section.blank {
+			item.write(&b)
+		}
+	}
+
+	return b.String()
+}
+
+// parsedHeading reports whether the heading is the parsed one.
+func (s *Section) parsedHeading() bool {
+	match := headingRe.FindStringSubmatch(s.heading)
+
+	return match != nil && len(match[1]) == s.Level && match[2] == s.Title
+}
+
+func (i *Item) write(b *strings.Builder) {
+	if i.IsRaw() {
+		b.WriteString(i.Raw + "\n")
+		return
+	}
+
+	b.WriteString(i.Indent + i.Marker + " ")
+	if i.Task {
+		if i.Checked {
+			b.WriteString("[x] ")
+		} else {
+			b.WriteString("[ ] ")
+		}
+	}
+	b.WriteString(i.Text + "\n")
+
+	for _, child := range i.Children {
+		child.write(b)
+	}
+}
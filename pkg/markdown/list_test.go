@@ -0,0 +1,107 @@
+package markdown
+
+import "testing"
+
+func TestParseStringRoundTrip(t *testing.T) {
+	tests := map[string]string{
+		"empty":    "",
+		"list":     "- [ ] milk\n- [x] bread\n    - rye\n",
+		"blank":    "intro\n\n- milk\n\n\n- bread\n",
+		"headings": "# Shopping\n\n## Dairy\n- milk\n\n##  Bakery  \n- bread\n",
+		"no blank": "- milk\n## Dairy\n- cheese\n",
+		"trailing": "## Dairy\n- milk\n\n\n",
+		"code":     "# Notes\n```bash\n# not a heading\n\n- not an item\n```\n- item\n",
+		"tilde":    "~~~\n## text\n~~~\n",
+		"spaces":   "- milk\n   \n- bread\n",
+		"front":    "---\ntags:\n  - shopping\n...\n- milk\n",
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Parse(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := doc.String(); got != text {
+				t.Errorf("String() = %q, want %q", got, text)
+			}
+		})
+	}
+}
+
+func TestParseCodeBlock(t *testing.T) {
+	doc, err := Parse("- milk\n```\n# comment\n- [ ] not a task\n```\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Sections) != 1 {
+		t.Errorf("Parse() has %d sections, want 1", len(doc.Sections))
+	}
+
+	var items []string
+	doc.Walk(func(_ *Section, item *Item, _ int) {
+		items = append(items, item.Text)
+	})
+
+	if len(items) != 1 || items[0] != "milk" {
+		t.Errorf("Walk() items = %q, want [milk]", items)
+	}
+}
+
+func TestAppendItems(t *testing.T) {
+	doc, err := Parse("## Dairy\n- milk\n\n## Bakery\n- bread\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dairy := doc.Section("dairy")
+	dairy.Items = append(dairy.Items, &Item{Marker: "-", Text: "cheese"})
+
+	fruit := doc.AddSection("Fruit", 2)
+	fruit.Items = append(fruit.Items, &Item{Marker: "-", Text: "apple"})
+
+	want := "## Dairy\n- milk\n- cheese\n\n## Bakery\n- bread\n\n## Fruit\n- apple\n"
+	if got := doc.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	doc, err := Parse("- milk\n    - whole\n\n- bread\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Remove(func(item *Item) bool { return item.Text == "milk" })
+
+	if got, want := doc.String(), "\n- bread\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseFrontmatter(t *testing.T) {
+	doc, err := Parse("---\ntags:\n  - shopping\naliases:\n  - groceries\n---\n- milk\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []string
+	doc.Walk(func(_ *Section, item *Item, _ int) {
+		items = append(items, item.Text)
+	})
+
+	if len(items) != 1 || items[0] != "milk" {
+		t.Errorf("Walk() items = %q, want [milk]", items)
+	}
+
+	doc.Remove(func(item *Item) bool { return true })
+	section := doc.AddSection("Dairy", 2)
+	section.Items = append(section.Items, &Item{Marker: "-", Text: "cheese"})
+
+	want := "---\ntags:\n  - shopping\naliases:\n  - groceries\n---\n## Dairy\n- cheese\n"
+	if got := doc.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
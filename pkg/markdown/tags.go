@@ -0,0 +1,142 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// span is the position of a tag in a line, including its "#".
+type span struct {
+	start, end int
+}
+
+// Tags returns the #tags of the text the way Obsidian recognizes them: a tag
+// starts after whitespace or at the beginning of a line, may be nested like
+// "#inbox/work" and is not made of digits only. Tags in code blocks and code
+// spans are skipped. The returned tags have no leading "#".
+func Tags(text string) []string {
+	var tags []string
+	walkTags(text, func(line string, spans []span) string {
+		for _, s := range spans {
+			tags = append(tags, line[s.start+1:s.end])
+		}
+
+		return line
+	})
+
+	return tags
+}
+
+// RemoveTag removes the tag and the tags nested into it from the text,
+// leaving code untouched.
+func RemoveTag(text, tag string) string {
+	return walkTags(text, func(line string, spans []span) string {
+		for i := len(spans) - 1; i >= 0; i-- {
+			s := spans[i]
+			if !HasTag([]string{line[s.start+1 : s.end]}, tag) {
+				continue
+			}
+
+			before, after := strings.TrimRight(line[:s.start], " \t"), line[s.end:]
+			if before == "" || strings.HasPrefix(after, " ") || strings.HasPrefix(after, "\t") {
+				after = strings.TrimLeft(after, " \t")
+				if before != "" && after != "" {
+					after = " " + after
+				}
+			}
+			line = before + after
+		}
+
+		return line
+	})
+}
+
+// walkTags calls fn with the tags of every line outside of code blocks and
+// joins the lines returned by fn back into the text.
+func walkTags(text string, fn func(line string, spans []span) string) string {
+	lines := strings.Split(text, "\n")
+
+	var fence string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if spans := lineTags(line); len(spans) > 0 {
+			lines[i] = fn(line, spans)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func lineTags(line string) []span {
+	var spans []span
+
+	prev := ' '
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+
+		switch {
+		case r == '`':
+			// skip the code span up to the closing backticks of the same length
+			ticks := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+			end := strings.Index(line[i+ticks:], line[i:i+ticks])
+			if end < 0 {
+				return spans
+			}
+			i += ticks + end + ticks
+			prev = '`'
+			continue
+		case r == '#' && unicode.IsSpace(prev):
+			tag := line[i+1:]
+			if end := strings.IndexFunc(tag, func(r rune) bool { return !isTagRune(r) }); end >= 0 {
+				tag = tag[:end]
+			}
+
+			tag = strings.TrimRight(tag, "/")
+			if strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+				spans = append(spans, span{start: i, end: i + 1 + len(tag)})
+			}
+
+			i += 1 + len(tag)
+			prev = '#'
+			continue
+		}
+
+		prev = r
+		i += size
+	}
+
+	return spans
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '/'
+}
+
+// HasTag reports whether the tags contain the tag itself or a tag nested
+// into it, so "inbox" matches "inbox/work". Tags are compared ignoring case.
+func HasTag(tags []string, tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+
+	for _, t := range tags {
+		t = strings.TrimPrefix(t, "#")
+		if strings.EqualFold(t, tag) || (len(t) > len(tag) && t[len(tag)] == '/' && strings.EqualFold(t[:len(tag)], tag)) {
+			return true
+		}
+	}
+
+	return false
+}
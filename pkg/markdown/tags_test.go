@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want []string
+	}{
+		"start":        {"#inbox idea", []string{"inbox"}},
+		"words":        {"read #book and #film", []string{"book", "film"}},
+		"punctuation":  {"see #work, #home. (#later) #done!", []string{"work", "home", "done"}},
+		"nested":       {"#inbox/work/urgent #project/", []string{"inbox/work/urgent", "project"}},
+		"symbols":      {"#to-do #snake_case #тег", []string{"to-do", "snake_case", "тег"}},
+		"digits":       {"#123 #2024 #y2024", []string{"y2024"}},
+		"no space":     {"a#b c##d", nil},
+		"heading":      {"# Title\n## Sub", nil},
+		"code span":    {"run `#not` and ``a ` #not`` #yes", []string{"yes"}},
+		"open span":    {"#yes `#not", []string{"yes"}},
+		"fence":        {"#yes\n```\n#not\n```\n#also", []string{"yes", "also"}},
+		"tilde fence":  {"~~~go\n#not\n~~~\n#yes", []string{"yes"}},
+		"open fence":   {"#yes\n```\n#not", []string{"yes"}},
+		"indented tab": {"\t#yes", []string{"yes"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Tags(tt.text); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Tags(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveTag(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		tag  string
+		want string
+	}{
+		"start":       {"#inbox idea", "inbox", "idea"},
+		"middle":      {"an #inbox idea", "inbox", "an idea"},
+		"end":         {"idea #inbox", "inbox", "idea"},
+		"punctuation": {"idea #inbox, later", "inbox", "idea, later"},
+		"nested":      {"idea #inbox/work", "inbox", "idea"},
+		"case":        {"idea #Inbox", "inbox", "idea"},
+		"prefix only": {"idea #inboxes", "inbox", "idea #inboxes"},
+		"code":        {"`#inbox` #inbox\n```\n#inbox\n```", "inbox", "`#inbox`\n```\n#inbox\n```"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := RemoveTag(tt.text, tt.tag); got != tt.want {
+				t.Errorf("RemoveTag(%q, %q) = %q, want %q", tt.text, tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	tags := []string{"#Inbox/work", "book"}
+
+	for tag, want := range map[string]bool{
+		"inbox":       true,
+		"#inbox/work": true,
+		"inbox/home":  false,
+		"in":          false,
+		"BOOK":        true,
+		"film":        false,
+	} {
+		if got := HasTag(tags, tag); got != want {
+			t.Errorf("HasTag(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
@@ -1,32 +1,73 @@
 package tgbot
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	tb "gopkg.in/telebot.v3"
 )
 
+const defaultPollTimeout = 10 * time.Second
+
 func NewBot(cfg *Config) (*tb.Bot, error) {
-	b, err := tb.NewBot(tb.Settings{
+	settings := tb.Settings{
 		Token:   cfg.Token,
 		Verbose: cfg.Verbose,
-	})
+	}
+
+	switch cfg.Mode {
+	case ModePolling:
+		timeout := cfg.PollTimeout
+		if timeout == 0 {
+			timeout = defaultPollTimeout
+		}
+
+		settings.Poller = &tb.LongPoller{Timeout: timeout}
+	default:
+		settings.Poller = webhookPoller{}
+	}
+
+	b, err := tb.NewBot(settings)
 	if err != nil {
 		return nil, fmt.Errorf("new bot: %w", err)
 	}
 
-	err = b.SetWebhook(&tb.Webhook{
+	switch cfg.Mode {
+	case ModePolling:
+		// Telegram refuses getUpdates while a webhook is set.
+		err = b.RemoveWebhook()
+		if err != nil {
+			return nil, fmt.Errorf("remove webhook: %w", err)
+		}
+	default:
+		err = setupWebhook(b, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof("Successfuly connect to tg api in %s mode and use bot with username %q", cfg.Mode, b.Me.Username)
+
+	return b, nil
+}
+
+func setupWebhook(b *tb.Bot, cfg *Config) error {
+	err := b.SetWebhook(&tb.Webhook{
 		Endpoint: &tb.WebhookEndpoint{
 			PublicURL: cfg.WebhookUrl,
 		},
+		SecretToken: cfg.SecretToken,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("set webhook: %w", err)
+		return fmt.Errorf("set webhook: %w", err)
 	}
 
+	updates := newUpdateCache(cfg.DedupCacheSize)
+
 	http.HandleFunc("/bot", func(w http.ResponseWriter, r *http.Request) {
 		log.Info("Received webhook request")
 		if r.Method != http.MethodPost {
@@ -35,6 +76,12 @@ func NewBot(cfg *Config) (*tb.Bot, error) {
 			return
 		}
 
+		if cfg.SecretToken != "" && !validSecretToken(r, cfg.SecretToken) {
+			log.Warn("Invalid secret token in webhook request")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
 		update := &tb.Update{}
 		if err := json.NewDecoder(r.Body).Decode(update); err != nil {
 			log.Errorf("Failed to decode request: %v", err)
@@ -42,12 +89,30 @@ func NewBot(cfg *Config) (*tb.Bot, error) {
 			return
 		}
 
+		if updates.Seen(update.ID) {
+			log.Infof("Skip duplicate update: updateID=%d", update.ID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
 		log.Infof("Processing update: %+v", update)
 		b.ProcessUpdate(*update)
 		w.WriteHeader(http.StatusOK)
 	})
 
-	log.Infof("Successfuly connect to tg api and use bot with username %q", b.Me.Username)
+	return nil
+}
+
+func validSecretToken(r *http.Request, secretToken string) bool {
+	got := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
 
-	return b, nil
+	return subtle.ConstantTimeCompare([]byte(got), []byte(secretToken)) == 1
+}
+
+// webhookPoller keeps bot.Start blocked without calling getUpdates,
+// updates are delivered by the /bot handler instead.
+type webhookPoller struct{}
+
+func (webhookPoller) Poll(_ *tb.Bot, _ chan tb.Update, stop chan struct{}) {
+	<-stop
 }
@@ -0,0 +1,32 @@
+package tgbot
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidSecretToken(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		token  string
+		want   bool
+	}{
+		"match":    {header: "s3cret", token: "s3cret", want: true},
+		"mismatch": {header: "guess", token: "s3cret"},
+		"prefix":   {header: "s3c", token: "s3cret"},
+		"missing":  {token: "s3cret"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/bot", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Telegram-Bot-Api-Secret-Token", tt.header)
+			}
+
+			if got := validSecretToken(r, tt.token); got != tt.want {
+				t.Errorf("validSecretToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,19 +1,51 @@
 package tgbot
 
-import "golang.org/x/xerrors"
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type Mode string
+
+const (
+	ModeWebhook Mode = "webhook"
+	ModePolling Mode = "polling"
+)
+
+// secretTokenRe matches the characters Telegram accepts in a webhook secret token.
+var secretTokenRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
 
 type Config struct {
-	Verbose    bool   `yaml:"verbose"`
-	Token      string `yaml:"token"`
-	WebhookUrl string `yaml:"webhook_url"`
+	Verbose        bool          `yaml:"verbose"`
+	Token          string        `yaml:"token"`
+	Mode           Mode          `yaml:"mode"`
+	WebhookUrl     string        `yaml:"webhook_url"`
+	SecretToken    string        `yaml:"secret_token"`
+	DedupCacheSize int           `yaml:"dedup_cache_size"`
+	PollTimeout    time.Duration `yaml:"poll_timeout"`
 }
 
 func ValidateConfig(config *Config) error {
+	if config.Mode == "" {
+		config.Mode = ModeWebhook
+	}
+
 	switch {
 	case config.Token == "":
 		return xerrors.New("\"token\" is required")
-	case config.WebhookUrl == "":
-		return xerrors.New("\"webhook_url\" is required")
+	case config.Mode != ModeWebhook && config.Mode != ModePolling:
+		return fmt.Errorf("unknown \"mode\" [mode = %q], expected %q or %q", config.Mode, ModeWebhook, ModePolling)
+	case config.Mode == ModeWebhook && config.WebhookUrl == "":
+		return xerrors.New("\"webhook_url\" is required in webhook mode")
+	case config.SecretToken != "" && !secretTokenRe.MatchString(config.SecretToken):
+		return xerrors.New("\"secret_token\" must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	case config.DedupCacheSize < 0:
+		return xerrors.New("\"dedup_cache_size\" must not be negative")
+	case config.PollTimeout < 0:
+		return xerrors.New("\"poll_timeout\" must not be negative")
 	}
 
 	return nil
@@ -0,0 +1,50 @@
+package tgbot
+
+import "sync"
+
+const defaultDedupCacheSize = 1000
+
+// updateCache remembers the most recent update IDs, so retried webhook
+// deliveries are processed only once.
+type updateCache struct {
+	mu    sync.Mutex
+	size  int
+	seen  map[int]struct{}
+	order []int
+	next  int
+}
+
+func newUpdateCache(size int) *updateCache {
+	if size <= 0 {
+		size = defaultDedupCacheSize
+	}
+
+	return &updateCache{
+		size:  size,
+		seen:  make(map[int]struct{}, size),
+		order: make([]int, 0, size),
+	}
+}
+
+// Seen reports whether the update ID was already registered and
+// registers it otherwise, evicting the oldest ID when the cache is full.
+func (c *updateCache) Seen(id int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.seen[id]; ok {
+		return true
+	}
+
+	if len(c.order) < c.size {
+		c.order = append(c.order, id)
+	} else {
+		delete(c.seen, c.order[c.next])
+		c.order[c.next] = id
+		c.next = (c.next + 1) % c.size
+	}
+
+	c.seen[id] = struct{}{}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package transcriber
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type Type string
+
+const (
+	TypeNone    Type = "none"
+	TypeCommand Type = "command"
+)
+
+const defaultTimeout = 2 * time.Minute
+
+// FilePlaceholder in Args is replaced with the path of the audio file.
+const FilePlaceholder = "{file}"
+
+type Config struct {
+	Type Type `yaml:"type"`
+	// Command and Args run the local binary, it gets the audio file through
+	// the "{file}" argument, or as the last argument without it, and prints
+	// the transcript to stdout.
+	Command string        `yaml:"command"`
+	Args    []string      `yaml:"args"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func ValidateConfig(config *Config) error {
+	if config.Type == "" {
+		config.Type = TypeNone
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	switch {
+	case config.Type != TypeNone && config.Type != TypeCommand:
+		return fmt.Errorf("unknown \"type\" [type = %q], expected %q or %q", config.Type, TypeNone, TypeCommand)
+	case config.Type == TypeCommand && config.Command == "":
+		return xerrors.New("\"command\" is required for the command transcriber")
+	case config.Timeout < 0:
+		return xerrors.New("\"timeout\" must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package transcriber turns voice messages into text.
+package transcriber
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type Transcriber interface {
+	// Transcribe returns the text of the audio, empty when it is unknown.
+	Transcribe(ctx context.Context, audio io.Reader) (string, error)
+}
+
+func New(config *Config) Transcriber {
+	if config.Type == TypeCommand {
+		return &command{Config: config}
+	}
+
+	return noop{}
+}
+
+// noop leaves voice notes without a transcript.
+type noop struct{}
+
+func (noop) Transcribe(ctx context.Context, audio io.Reader) (string, error) {
+	return "", nil
+}
+
+// command runs a local binary, e.g. whisper.cpp, on a temporary copy of
+// the audio.
+type command struct {
+	Config *Config
+}
+
+func (t *command) Transcribe(ctx context.Context, audio io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "voice-*.ogg")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, audio); err != nil {
+		file.Close()
+		return "", fmt.Errorf("write temp file [filepath = %q]: %w", file.Name(), err)
+	}
+
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("close temp file [filepath = %q]: %w", file.Name(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Config.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.Config.Command, t.args(file.Name())...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run %q: %w: %s", t.Config.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (t *command) args(fp string) []string {
+	args := make([]string, 0, len(t.Config.Args)+1)
+
+	var replaced bool
+	for _, arg := range t.Config.Args {
+		if strings.Contains(arg, FilePlaceholder) {
+			arg = strings.ReplaceAll(arg, FilePlaceholder, fp)
+			replaced = true
+		}
+		args = append(args, arg)
+	}
+
+	if !replaced {
+		args = append(args, fp)
+	}
+
+	return args
+}
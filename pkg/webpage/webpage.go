@@ -0,0 +1,182 @@
+// Package webpage fetches the title and description of web pages.
+package webpage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	// maxPageSize limits the downloaded part of the page, the head is enough.
+	maxPageSize = 1 << 20
+)
+
+var (
+	titleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	metaRe  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attrRe  = regexp.MustCompile(`(?is)([a-z:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	spaceRe = regexp.MustCompile(`\s+`)
+)
+
+var errNotPublic = errors.New("address is not public")
+
+// reservedPrefixes are ranges which are not reachable from the internet and
+// are not covered by the checks of netip.Addr.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+type Page struct {
+	Title       string
+	Description string
+}
+
+type fetcher struct {
+	Client *http.Client
+}
+
+// NewFetcher returns a fetcher using the client. When it is nil, a client
+// with a timeout which connects to public addresses only is used, so links
+// sent to the bot can't reach the host or its network, redirects included.
+func NewFetcher(client *http.Client) *fetcher {
+	if client == nil {
+		client = &http.Client{
+			Timeout:   defaultTimeout,
+			Transport: publicTransport(),
+		}
+	}
+
+	return &fetcher{Client: client}
+}
+
+func publicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: defaultTimeout,
+		// the resolved address is checked, so DNS can't point to the host
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return checkPublic(address)
+		},
+	}
+
+	return &http.Transport{
+		// a proxy would be checked instead of the page, so none is used
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   defaultTimeout,
+		ResponseHeaderTimeout: defaultTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
+
+// checkPublic rejects loopback, private, link-local and other addresses
+// which are not reachable from the internet.
+func checkPublic(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("split address [address = %q]: %w", address, err)
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("parse address [address = %q]: %w", address, err)
+	}
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("dial [address = %q]: %w", address, errNotPublic)
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("dial [address = %q]: %w", address, errNotPublic)
+		}
+	}
+
+	return nil
+}
+
+// Fetch downloads the page and reads its title and description, Open Graph
+// properties are preferred over the title tag and the description meta tag.
+func (f *fetcher) Fetch(ctx context.Context, url string) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get page [url = %q]: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get page [url = %q]: unexpected status %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("read page [url = %q]: %w", url, err)
+	}
+
+	return Parse(decode(data, resp.Header.Get("Content-Type"))), nil
+}
+
+// Parse reads the title and description from the HTML of the page decoded
+// to UTF-8.
+func Parse(data string) *Page {
+	page := &Page{}
+	if match := titleRe.FindStringSubmatch(data); match != nil {
+		page.Title = clean(match[1])
+	}
+
+	var ogTitle, ogDescription, description string
+	for _, tag := range metaRe.FindAllString(data, -1) {
+		attrs := make(map[string]string)
+		for _, attr := range attrRe.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(attr[1])] = attr[2] + attr[3]
+		}
+
+		name := strings.ToLower(attrs["property"] + attrs["name"])
+		switch name {
+		case "og:title":
+			ogTitle = clean(attrs["content"])
+		case "og:description":
+			ogDescription = clean(attrs["content"])
+		case "description":
+			description = clean(attrs["content"])
+		}
+	}
+
+	if ogTitle != "" {
+		page.Title = ogTitle
+	}
+
+	page.Description = description
+	if ogDescription != "" {
+		page.Description = ogDescription
+	}
+
+	return page
+}
+
+func clean(text string) string {
+	return strings.TrimSpace(spaceRe.ReplaceAllString(html.UnescapeString(text), " "))
+}
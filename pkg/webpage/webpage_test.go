@@ -0,0 +1,112 @@
+package webpage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/og":
+			w.Write([]byte(`<html><head><title>Plain</title>
+<meta property="og:title" content="Open &amp; Graph">
+<meta name="description" content="meta description">
+<meta property="og:description" content=" og   description ">
+</head></html>`))
+		case "/cp1251":
+			w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+			// "Привет" in windows-1251
+			w.Write([]byte("<title>\xcf\xf0\xe8\xe2\xe5\xf2</title>"))
+		case "/meta":
+			w.Header().Set("Content-Type", "text/html")
+			// "Привет" in koi8-r
+			w.Write([]byte(`<meta http-equiv="Content-Type" content="text/html; charset=koi8-r"><title>` + "\xf0\xd2\xc9\xd7\xc5\xd4</title>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		path  string
+		want  Page
+		isErr bool
+	}{
+		{path: "/og", want: Page{Title: "Open & Graph", Description: "og description"}},
+		{path: "/cp1251", want: Page{Title: "Привет"}},
+		{path: "/meta", want: Page{Title: "Привет"}},
+		{path: "/missing", isErr: true},
+	}
+
+	// the test server listens on loopback, which the default client rejects
+	fetcher := NewFetcher(srv.Client())
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			page, err := fetcher.Fetch(context.Background(), srv.URL+tt.path)
+			if (err != nil) != tt.isErr {
+				t.Fatalf("Fetch() error = %v, isErr %v", err, tt.isErr)
+			}
+
+			if err == nil && *page != tt.want {
+				t.Errorf("Fetch() = %+v, want %+v", *page, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchRejectsLocalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<title>secret</title>"))
+	}))
+	defer srv.Close()
+
+	_, err := NewFetcher(nil).Fetch(context.Background(), srv.URL)
+	if !errors.Is(err, errNotPublic) {
+		t.Errorf("Fetch() error = %v, want %v", err, errNotPublic)
+	}
+}
+
+func TestCheckPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34:443":          true,
+		"[2606:4700:4700::1111]:443": true,
+		"127.0.0.1:80":               false,
+		"10.1.2.3:80":                false,
+		"192.168.0.1:80":             false,
+		"169.254.169.254:80":         false,
+		"100.64.0.1:80":              false,
+		"0.0.0.0:80":                 false,
+		"[::1]:80":                   false,
+		"[fe80::1]:80":               false,
+		"[fd00::1]:80":               false,
+		"[::ffff:127.0.0.1]:80":      false,
+	}
+
+	for address, public := range tests {
+		if err := checkPublic(address); (err == nil) != public {
+			t.Errorf("checkPublic(%q) = %v, want public %v", address, err, public)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		data, contentType, want string
+	}{
+		{data: "caf\xc3\xa9", want: "café"},
+		{data: "caf\xe9", contentType: "text/html; charset=ISO-8859-1", want: "café"},
+		{data: `<meta charset="windows-1252">` + "\x93q\x94", want: `<meta charset="windows-1252">“q”`},
+		{data: "bad \xff", contentType: "text/html; charset=unknown", want: "bad �"},
+	}
+
+	for _, tt := range tests {
+		if got := decode([]byte(tt.data), tt.contentType); got != tt.want {
+			t.Errorf("decode(%q, %q) = %q, want %q", tt.data, tt.contentType, got, tt.want)
+		}
+	}
+}